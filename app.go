@@ -20,24 +20,33 @@
 //	}
 //
 // Build an [App] with [NewApp], register commands with [App.Add], [App.Default], and [App.Help],
-// then dispatch os.Args with [App.Run]. The framework merges values from struct defaults,
+// then dispatch os.Args with [App.Run] (or [App.RunContext] to supply your own context). The framework merges values from struct defaults,
 // the resolver chain (see [Resolver]), environment variables, and parsed flags, in that order
 // of increasing precedence, before calling Run.
 //
+// Run owns a signal-derived context: the first Ctrl-C (or SIGTERM) cancels it, a second one force-exits.
+// Commands read it through [BaseCommand.Context], or receive it directly by implementing [ContextRunner].
+//
 // Help (-h/--help) and version (-V/--version) are built in.
 // Run returns the [ErrShowingHelp] / [ErrShowingVersion] sentinels once it has handled those requests itself;
 // use [IsRealError] to filter them at the call site.
+// Or let [App.Main] do both: it runs os.Args[1:], renders errors to stderr, and exits with the code [ExitCode] maps them to.
 //
 // File-backed configuration, output codecs, the help command, and the docs generator live in sub-packages
 // so the core stays dependency-light. See the runnable programs under examples/ for complete applications.
 package cli
 
 import (
+	"bufio"
+	"context"
 	"errors"
 	"fmt"
-	"os"
+	"io"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/toaweme/structs"
 )
@@ -68,6 +77,7 @@ const (
 	argHelpValues = "help-values"
 	argHelpFormat = "help-format"
 	argVersion    = "version"
+	argNoInput    = "no-input"
 )
 
 // IsRealError reports whether err is a genuine failure worth surfacing, as opposed to a clean-exit sentinel
@@ -89,7 +99,7 @@ func IsRealError(err error) bool {
 // App is the top-level CLI application. It owns the command set, global flags,
 // and an ordered chain of config Resolvers, and dispatches osArgs to the matched command.
 type App interface {
-	// Commands returns the registered top-level commands.
+	// Commands returns the registered top-level commands, followed by any plugins found on PATH (see Plugins).
 	Commands() []Command[any]
 	// DefaultCommand returns the command registered via Default, or nil when none is set.
 	DefaultCommand() Command[any]
@@ -111,7 +121,51 @@ type App interface {
 	Add(name string, cmd Command[any]) Command[any]
 	// Run parses osArgs and dispatches to the matched command.
 	// Help and version requests surface as the ErrShowingHelp/ErrShowingVersion sentinels.
+	// The command runs under a context the framework cancels on the first SIGINT/SIGTERM.
 	Run(osArgs []string) error
+	// RunContext is Run with a caller-supplied context, handed to the matched command as is.
+	// No signal handling is installed; derive ctx from signal.NotifyContext for that.
+	RunContext(ctx context.Context, osArgs []string) error
+	// Main runs os.Args[1:], prints a genuine error to stderr, and exits the process with ExitCode(err).
+	Main()
+	// PreRun registers hooks run before every command's Run, once its inputs are resolved, and returns the app for chaining.
+	// Commands register their own (and persistent, inherited) hooks through BaseCommand.
+	PreRun(hooks ...Hook) App
+	// PostRun registers hooks run after every command's Run succeeds, and returns the app for chaining.
+	PostRun(hooks ...Hook) App
+	// OnError registers hooks that receive every command's Run error, and returns the app for chaining.
+	OnError(hooks ...ErrorHook) App
+	// IO sets the stdin, stdout, stderr, and environment the app's built-ins (help, completion, version,
+	// warnings, .env loading) and its commands use, and returns the app for chaining. Unset fields fall back
+	// to the process's own. Commands read them with BaseCommand.IO.
+	IO(streams IO) App
+	// DotEnv registers .env files (".env" when none is given) loaded into the app's environment at the start
+	// of every run, without overriding variables already set, and returns the app for chaining.
+	DotEnv(paths ...string) App
+	// Prompter sets how required inputs still empty after the merge are asked for instead of failing
+	// validation, and returns the app for chaining. The default asks on stdin when it is a terminal;
+	// --no-input, or no terminal, keeps the failure.
+	Prompter(p Prompter) App
+	// GracePeriod sets how long Run waits for the command to return after the first interrupt
+	// before force-exiting, and returns the app for chaining. Zero (the default) waits for a second interrupt.
+	GracePeriod(timeout time.Duration) App
+	// ResponseFiles turns expansion of "@file" arguments on or off (off by default) and returns the app for
+	// chaining: each "@path" is replaced by the shell-quoted arguments read from path, recursively,
+	// before anything is parsed. "@@x" passes a literal "@x".
+	ResponseFiles(enabled bool) App
+	// ShortFlagClustering turns getopt-style short flags on or off (off by default) and returns the app
+	// for chaining: "-abc" is "-a -b -c" when all three are bool shorts, and "-p8080" binds 8080 to -p.
+	// Off, a token like "-vv" names the flag whose short tag is "vv", as before.
+	ShortFlagClustering(enabled bool) App
+	// Complete returns the completion candidates for the last of args given the words before it,
+	// as the shell completion scripts receive them: subcommand names and positional values, flags for a word
+	// starting with "-", or a flag's values after "--flag " or "--flag=" (see Completer).
+	Complete(args []string) []Completion
+	// Plugins enables git-style external commands and returns the app for chaining: when no built-in command
+	// matches, an executable named <app name>-<cmd> on PATH runs in its place, with the remaining args and the
+	// CLI_APP_* / CLI_PLUGIN_* environment describing the app. Plugins answering the `__describe` handshake
+	// with help.CommandInfo JSON are listed by help, completion, and gendocs next to the built-in commands.
+	Plugins() App
 	// Help registers cmd as the command that renders help, so callers never have to know the reserved name.
 	// Use it instead of Add: app.Help(help.NewHelpCommand(...)).
 	Help(cmd Command[any]) Command[any]
@@ -124,6 +178,29 @@ type app struct {
 	globalFlags    *GlobalFlags
 	commands       []Command[any]
 	defaultCommand Command[any]
+	grace          time.Duration
+	// exit replaces os.Exit for Main and the force exit on a second signal, so tests can observe them.
+	exit     func(code int)
+	appHooks hookSet
+	// deprecationsWarned records the deprecation warnings already printed, so each shows once.
+	deprecationsWarned map[string]bool
+	// plugins enables PATH plugin commands; discovered caches those found, once listed.
+	plugins    bool
+	discovered []Command[any]
+	streams    IO
+	// input buffers streams.In for every run and command (see IO.Reader); inputOf is the In it reads.
+	inputMu  sync.Mutex
+	input    *bufio.Reader
+	inputOf  io.Reader
+	dotenvs  []string
+	prompter Prompter
+	// responseFiles enables "@file" argument expansion.
+	responseFiles bool
+	// clusterShorts enables getopt-style short flags ("-abc", "-p8080").
+	clusterShorts bool
+	// globalDefaults is the GlobalFlags the app was built with, restored at the start of every run
+	// so one run's flags don't leak into the next (see commands/shell).
+	globalDefaults *GlobalFlags
 }
 
 var _ App = (*app)(nil)
@@ -139,9 +216,15 @@ func NewApp(config Config, opts GlobalFlags) App {
 	}
 }
 
-// Commands returns the registered top-level commands, in registration order.
+// Commands returns the registered top-level commands, in registration order,
+// followed by the plugins found on PATH when Plugins is enabled.
 func (c *app) Commands() []Command[any] {
-	return c.commands
+	plugins := c.pluginCommands()
+	if len(plugins) == 0 {
+		return c.commands
+	}
+
+	return append(slices.Clone(c.commands), plugins...)
 }
 
 // DefaultCommand returns the command registered via Default, or nil when none is set.
@@ -149,6 +232,17 @@ func (c *app) DefaultCommand() Command[any] {
 	return c.defaultCommand
 }
 
+// resetGlobalFlags restores the global flags to the values the app was built with, once a run has
+// parsed into them, so flags given to one run don't carry over to the next run of the same app.
+func (c *app) resetGlobalFlags() {
+	if c.globalDefaults == nil {
+		defaults := *c.globalFlags
+		c.globalDefaults = &defaults
+		return
+	}
+	*c.globalFlags = *c.globalDefaults
+}
+
 // Config returns the application's Config (name, version, merge strategy).
 func (c *app) Config() Config {
 	return c.config
@@ -176,6 +270,7 @@ func (c *app) HelpOutputs(formats ...OutputCodec) App {
 // Default registers the command Run dispatches to when invoked with no arguments. It returns cmd.
 func (c *app) Default(cmd Command[any]) Command[any] {
 	c.defaultCommand = cmd
+	bindEnvPrefix(cmd, c.config.EnvPrefix)
 
 	return cmd
 }
@@ -184,6 +279,7 @@ func (c *app) Default(cmd Command[any]) Command[any] {
 // app.Add("db", db).Add("migrate", migrate).
 func (c *app) Add(name string, cmd Command[any]) Command[any] {
 	cmd.Name(name)
+	bindEnvPrefix(cmd, c.config.EnvPrefix)
 	c.commands = append(c.commands, cmd)
 
 	return cmd
@@ -193,16 +289,58 @@ func (c *app) Add(name string, cmd Command[any]) Command[any] {
 // resolves and validates global options, then dispatches to the matched command.
 // A --help or --version request, and an unknown command, surface as the
 // ErrShowingHelp / ErrShowingVersion sentinels - test with errors.Is and treat them as clean exits.
+// A mistyped command also wraps an *UnknownCommandError carrying "did you mean" suggestions (see errors.As).
+// The command runs under a context cancelled on the first SIGINT/SIGTERM (see GracePeriod).
 func (c *app) Run(osArgs []string) error {
+	ctx, stop := c.signalContext(context.Background())
+	defer stop()
+
+	return c.RunContext(ctx, osArgs)
+}
+
+// RunContext is Run with a caller-supplied context: ctx reaches the matched command unchanged,
+// through RunContext when the command implements ContextRunner and through BaseCommand.Context otherwise.
+func (c *app) RunContext(ctx context.Context, osArgs []string) error {
 	if len(c.commands) < 1 {
 		return ErrNoCommands
 	}
+	c.resetGlobalFlags()
+
+	if len(c.dotenvs) > 0 {
+		if err := loadDotEnv(c.stdio().Env, c.dotenvs); err != nil {
+			return err
+		}
+	}
 
 	if len(osArgs) > 0 && osArgs[0] == "__complete" {
 		c.handleComplete(osArgs[1:])
 		return nil
 	}
 
+	// response files expand first, so everything downstream (plugins included) sees the arguments
+	// as if typed out in full.
+	if c.responseFiles {
+		expanded, _, err := expandResponseFiles(osArgs, "", 0)
+		if err != nil {
+			return err
+		}
+		osArgs = expanded
+	}
+
+	// a plugin owns everything after its name, --help and --version included, so it is dispatched
+	// before the app interprets any of it.
+	if path, name, index, ok := c.findPlugin(osArgs); ok {
+		return c.runPlugin(ctx, path, name, osArgs, index)
+	}
+
+	if c.clusterShorts {
+		expanded, err := expandShortFlags(osArgs, c.runFields(osArgs))
+		if err != nil {
+			return err
+		}
+		osArgs = expanded
+	}
+
 	globalFlags, globalUnknownOpts := c.getGlobalFlags(osArgs)
 
 	// --help-format spans the built-in formats plus any output codecs registered via HelpOutputs,
@@ -233,6 +371,9 @@ func (c *app) Run(osArgs []string) error {
 	if c.globalFlags.HelpValues {
 		c.globalFlags.Help = true
 	}
+	if !c.globalFlags.NoInput {
+		c.globalFlags.NoInput = boolFlagRequested(osArgs, globalBoolFlagNames(argNoInput))
+	}
 
 	if c.globalFlags.Version {
 		c.printVersion()
@@ -242,12 +383,19 @@ func (c *app) Run(osArgs []string) error {
 	// commandArgs holds the osArgs that are commands
 	// allArgs holds the osArgs that are not commands
 	command, commandArgs, allArgs, err := c.matchCommandByArgs(osArgs)
+	fellBack := false
 	if err != nil {
 		// no command matched. with a default command set (and not an explicit --help), dispatch to it
 		// with the args parsed against it, so `app --flag` behaves like `app <default> --flag`
 		// and bare `app` runs the default. otherwise show help.
 		if !errors.Is(err, ErrCommandNotFound) || c.defaultCommand == nil || c.globalFlags.Help {
-			helpErr := c.runHelp(commandArgs, globalUnknownOpts)
+			// a typed command name that matched nothing is reported with suggestions;
+			// a bare invocation (no command at all) just shows help.
+			if name, _ := c.firstPositional(osArgs); name != "" && errors.Is(err, ErrCommandNotFound) {
+				err = unknownCommand(name, nil, c.commands)
+				ctx = withHelpCause(ctx, err)
+			}
+			helpErr := c.runHelp(ctx, commandArgs, globalUnknownOpts)
 			if helpErr != nil {
 				return fmt.Errorf("failed to run help: %w", helpErr)
 			}
@@ -257,6 +405,17 @@ func (c *app) Run(osArgs []string) error {
 		command = c.defaultCommand
 		commandArgs = nil
 		allArgs = osArgs
+		fellBack = true
+	}
+
+	chain := c.commandChain(commandArgs)
+	if len(chain) == 0 {
+		chain = []Command[any]{command}
+	}
+	for _, cmd := range chain {
+		if r, ok := cmd.(inputsResetter); ok {
+			r.resetInputs()
+		}
 	}
 
 	commandInputs := command.Options()
@@ -269,10 +428,19 @@ func (c *app) Run(osArgs []string) error {
 	// cmdUnknownArgs are the args that are not defined in the struct
 	// commandOptions are the options defined in the struct e.g. `arg:"cwd"`
 	// cmdUnknownOptions are the options that are not defined in the struct
-	cmdArgs, cmdUnknownArgs, commandOptions, cmdUnknownOptions := getCommandArgs(allArgs, commandFields)
+	// persistent flags of the ancestors (see Persistent) parse alongside the command's own,
+	// which come first so they win a name clash; routeInherited then hands them to their owners.
+	inherited, err := inheritedChain(chain)
+	if err != nil {
+		return err
+	}
+	parseFields := append(slices.Clone(commandFields), inheritedFields(inherited)...)
+	cmdArgs, cmdUnknownArgs, commandOptions, cmdUnknownOptions := getCommandArgs(allArgs, parseFields)
+	routeInherited(commandOptions, commandFields, inherited)
 	unknowns := Unknowns{
-		Args:    cmdUnknownArgs,
-		Options: cmdUnknownOptions,
+		Args:        cmdUnknownArgs,
+		Options:     cmdUnknownOptions,
+		Passthrough: passthroughArgs(allArgs),
 	}
 
 	// commandOptions holds the parsed flags; fold in positions keyed by index
@@ -288,11 +456,24 @@ func (c *app) Run(osArgs []string) error {
 	// and report it as not found. This runs before the --help check so a typo'd command is
 	// still reported even when --help is also passed. The help command is the one exception:
 	// it legitimately takes a command path as its positional arguments.
+	// Suggestions come from the level the lookup stopped at: the matched command's subcommands,
+	// or the top-level commands (and the default's subcommands) when we fell back to the default.
 	if len(cmdUnknownArgs) > 0 && command.Name("") != helpCommand {
-		if helpErr := c.runHelp(commandArgs, globalUnknownOpts); helpErr != nil {
+		candidates := command.Commands()
+		if fellBack {
+			candidates = append(slices.Clone(c.commands), candidates...)
+		}
+		notFound := unknownCommand(cmdUnknownArgs[0], commandArgs, candidates)
+		if helpErr := c.runHelp(withHelpCause(ctx, notFound), commandArgs, globalUnknownOpts); helpErr != nil {
 			return fmt.Errorf("failed to run help: %w", helpErr)
 		}
-		return fmt.Errorf("%w: %w", ErrCommandNotFound, ErrShowingHelp)
+		return fmt.Errorf("%w: %w", notFound, ErrShowingHelp)
+	}
+
+	// unknown flags pass through to the command, but one a typo away from a declared flag is
+	// almost certainly a mistake, so say so without failing the run.
+	for _, warning := range c.unknownFlagWarnings(cmdUnknownOptions, parseFields) {
+		fmt.Fprintf(c.stdio().Err, "warning: %v\n", warning)
 	}
 
 	// if --help is passed, show help
@@ -300,12 +481,15 @@ func (c *app) Run(osArgs []string) error {
 		// with --help-values, populate the matched command's struct so help can show resolved values.
 		// Skip validation (the resolve-only path) so --help still works when required inputs are absent.
 		if c.globalFlags.HelpValues {
+			if err := c.loadInherited(inherited, true); err != nil {
+				return err
+			}
 			if err := c.resolveCommandConfig(command, strings.Join(commandArgs, " "), flags); err != nil {
 				return err
 			}
 		}
 
-		err := c.runHelp(commandArgs, globalUnknownOpts)
+		err := c.runHelp(ctx, commandArgs, globalUnknownOpts)
 		if err != nil {
 			return fmt.Errorf("failed to run help: %w", err)
 		}
@@ -316,14 +500,18 @@ func (c *app) Run(osArgs []string) error {
 	// cmdPath is the matched command path (e.g. "db migrate"),
 	// handed to the resolver so it can apply per-command rules.
 	cmdPath := strings.Join(commandArgs, " ")
-	if err := c.loadCommandConfig(command, cmdPath, flags); err != nil {
+	c.warnDeprecatedCommands(chain)
+	if err := c.loadInherited(inherited, false); err != nil {
+		return err
+	}
+	if err := c.loadCommandConfig(ctx, command, cmdPath, flags); err != nil {
 		return err
 	}
 
-	err = command.Run(*c.globalFlags, unknowns)
+	err = c.execute(ctx, chain, unknowns)
 	if err != nil {
 		if errors.Is(err, ErrDisplaySubCommands) {
-			return c.runHelp(commandArgs, globalUnknownOpts)
+			return c.runHelp(ctx, commandArgs, globalUnknownOpts)
 		}
 		return fmt.Errorf("failed to run command %q: %w", command.Name(""), err)
 	}
@@ -331,11 +519,33 @@ func (c *app) Run(osArgs []string) error {
 	return nil
 }
 
+// runCommand dispatches to cmd under ctx: a ContextRunner receives ctx directly, any other
+// command has it bound (when it embeds BaseCommand) and is run through its plain Run.
+// Either way a command embedding BaseCommand has streams bound for BaseCommand.IO.
+func runCommand(ctx context.Context, streams IO, cmd Command[any], options GlobalFlags, unknowns Unknowns) error {
+	if binder, ok := cmd.(ioBinder); ok {
+		binder.bindIO(streams)
+	}
+	if runner, ok := cmd.(ContextRunner); ok {
+		return runner.RunContext(ctx, options, unknowns)
+	}
+	if binder, ok := cmd.(contextBinder); ok {
+		binder.bindContext(ctx)
+	}
+
+	return cmd.Run(options, unknowns)
+}
+
 func (c *app) matchCommandByArgs(args []string) (Command[any], []string, []string, error) {
 	var command Command[any]
 	var commandNameIndexes []int
 
 	for a := range args {
+		// nothing after a terminator names a command.
+		if args[a] == argsTerminator {
+			break
+		}
+
 		// previous arg is a command
 		// assert if this arg is a sub command
 		if command != nil {
@@ -388,17 +598,21 @@ func exists(slice []int, val int) bool {
 	return false
 }
 
+// matchCommandByName returns the command named arg, or answering to it as an alias, or nil.
+// An exact name wins over an alias, so an alias can never shadow a sibling command's name.
 func (c *app) matchCommandByName(arg string, commands []Command[any]) Command[any] {
-	var command Command[any]
 	for i := range commands {
-		cmd := commands[i]
-		if cmd.Name("") == arg {
-			command = cmd
-			break
+		if commands[i].Name("") == arg {
+			return commands[i]
+		}
+	}
+	for i := range commands {
+		if slices.Contains(CommandAliases(commands[i]), arg) {
+			return commands[i]
 		}
 	}
 
-	return command
+	return nil
 }
 
 // loadCommandConfig populates command.Options() from ordered layers and then validates the result.
@@ -414,30 +628,45 @@ func (c *app) matchCommandByName(arg string, commands []Command[any]) Command[an
 // Applying the merged map and the flags as distinct structs.Set passes is what makes flags beat env:
 // within a single pass, an `env:` tag match short-circuits, so a merged map cannot express "flags over env".
 // Validation runs after the merge so `required` is satisfied by config- or default-provided values, not just flags.
-func (c *app) loadCommandConfig(command Command[any], cmd string, flags map[string]any) error {
+func (c *app) loadCommandConfig(ctx context.Context, command Command[any], cmd string, flags map[string]any) error {
 	if err := c.resolveCommandConfig(command, cmd, flags); err != nil {
 		return err
 	}
+	if err := c.promptMissing(ctx, command.Options(), flags); err != nil {
+		return fmt.Errorf("failed to read input for command %q: %w", command.Name(""), err)
+	}
 
 	// validate against the explicit inputs the user supplied; rules like `required` fall back to the
 	// now-populated field values, so values sourced from config or defaults still satisfy them.
-	validateInputs := map[string]any{}
-	env(validateInputs)
-	for k, v := range flags {
-		validateInputs[k] = v
-	}
-	if err := command.Validate(validateInputs); err != nil {
+	// The rules relating fields to each other are checked over the merged values and reported alongside.
+	err := command.Validate(c.validationInputs(command.Options(), flags))
+	if err := joinValidation(err, checkRelations(command.Options(), envPrefixOf(command))); err != nil {
 		return fmt.Errorf("failed to validate command %q: %w", command.Name(""), err)
 	}
 
 	return nil
 }
 
-// env folds the process environment into commandOptions, keyed by variable name,
+// validationInputs is what options are validated against: the environment, the env vars bound through
+// Config.EnvPrefix, and flags, in increasing precedence.
+func (c *app) validationInputs(options any, flags map[string]any) map[string]any {
+	inputs := map[string]any{}
+	c.env(inputs)
+	if fields, err := structs.GetStructFields(options, nil, structs.DefaultEncodingTags); err == nil {
+		c.derivedEnv(fields, inputs)
+	}
+	for k, v := range flags {
+		inputs[k] = v
+	}
+
+	return inputs
+}
+
+// env folds the app's environment (see App.IO) into commandOptions, keyed by variable name,
 // so fields are matched by their `env:` tag during the merge. The framework folds
 // env in after the resolver chain and before flags, so env beats files but loses to a typed flag.
-func env(commandOptions map[string]any) {
-	for _, e := range os.Environ() {
+func (c *app) env(commandOptions map[string]any) {
+	for _, e := range c.stdio().Env.Environ() {
 		pair := strings.SplitN(e, "=", 2)
 		if len(pair) == 2 {
 			commandOptions[pair[0]] = pair[1]
@@ -450,7 +679,12 @@ func env(commandOptions map[string]any) {
 // which needs the resolved field values to display but must not fail when a required input is absent
 // (the user only asked for help).
 func (c *app) resolveCommandConfig(command Command[any], cmd string, flags map[string]any) error {
-	inputs := command.Options()
+	return c.resolveConfig(command.Options(), command.Name(""), cmd, flags)
+}
+
+// resolveConfig is resolveCommandConfig over an arbitrary inputs struct (a command's options, or an
+// ancestor's persistent part of them); name is the owning command's name, for errors.
+func (c *app) resolveConfig(inputs any, name, cmd string, flags map[string]any) error {
 	manager := structs.New(inputs, structs.WithTags(defaultTags...))
 
 	// run the resolver chain, threading each one's output into the next.
@@ -458,25 +692,44 @@ func (c *app) resolveCommandConfig(command Command[any], cmd string, flags map[s
 	for _, resolver := range c.resolvers {
 		next, err := resolver.Resolve(cmd, values)
 		if err != nil {
-			return fmt.Errorf("failed to resolve config for command %q: %w", command.Name(""), err)
+			return fmt.Errorf("failed to resolve config for command %q: %w", name, err)
 		}
 		if next != nil {
 			values = next
 		}
 	}
 
+	fields, err := structs.GetStructFields(inputs, nil, structs.DefaultEncodingTags)
+	if err != nil {
+		return fmt.Errorf("failed to get struct fields: %w", err)
+	}
+
 	// env beats the resolver layers; flags (applied below) still win over env.
-	env(values)
+	c.env(values)
+	c.derivedEnv(fields, values)
+
+	// deprecated flags and env vars warn and forward to their replacement before either layer is applied.
+	applyEnvNames(fields, c.config.EnvPrefix)
+	c.forwardDeprecated(fields, values, flags)
 
-	// defaults + resolved layer; an empty map still applies struct `default:` tags.
+	// defaults + resolved layer; an empty map still applies struct `default:` tags. Fields of a type
+	// parsed from text (see Value) are decoded first, and kept from structs.
+	values, err = c.decodeValues(fields, values, false)
+	if err != nil {
+		return fmt.Errorf("failed to apply resolved config for command %q: %w", name, err)
+	}
 	if err := manager.Set(values); err != nil {
-		return fmt.Errorf("failed to apply resolved config for command %q: %w", command.Name(""), err)
+		return fmt.Errorf("failed to apply resolved config for command %q: %w", name, err)
 	}
 
 	// flags win, as a separate pass.
 	if len(flags) > 0 {
-		if err := manager.Set(flags); err != nil {
-			return fmt.Errorf("failed to apply flags for command %q: %w", command.Name(""), err)
+		rest, err := c.decodeValues(fields, flags, true)
+		if err != nil {
+			return fmt.Errorf("failed to apply flags for command %q: %w", name, err)
+		}
+		if err := manager.Set(rest); err != nil {
+			return fmt.Errorf("failed to apply flags for command %q: %w", name, err)
 		}
 	}
 
@@ -2,83 +2,346 @@ package cli
 
 import (
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
 
 	"github.com/toaweme/structs"
 )
 
+// The directives ending a `__complete` answer (":<n>"), a bit set the shell scripts act on.
 const (
-	shellCompDirectiveNoFileComp = 4
+	shellCompDirectiveError         = 1
+	shellCompDirectiveNoSpace       = 2
+	shellCompDirectiveNoFileComp    = 4
+	shellCompDirectiveFilterFileExt = 8
+	shellCompDirectiveFilterDirs    = 16
 )
 
+// tagComplete hands a field's value to the shell's own path completion: complete:"file" for any file,
+// complete:"file:.yaml,.yml" for files with those extensions (and directories to reach them), and
+// complete:"dir" for directories only.
+const tagComplete = "complete"
+
+// Completion is one candidate for the word being completed: a command name, a --flag, or a value,
+// with its help.
+type Completion struct {
+	Value string
+	Help  string
+}
+
+// Completer is implemented by a command that suggests values for its flags and positionals beyond
+// the ones the App knows from its tags (oneof values, true/false for a bool), such as cluster names
+// read from a config file. Its candidates follow those; the shell filters them all by the prefix.
+type Completer interface {
+	CompleteValues(req CompletionRequest) []Completion
+}
+
+// CompletionRequest is the value a Completer is asked to complete.
+type CompletionRequest struct {
+	// Flag is the flag whose value is being completed, by its name without dashes ("cluster",
+	// "database.host") whichever of its names was typed; "" for a positional.
+	Flag string
+	// Position is the index of the positional being completed, 0 for the first; -1 for a flag value.
+	Position int
+	// Prefix is what has been typed of the value so far, possibly "".
+	Prefix string
+}
+
+// handleComplete answers the `__complete` request the shell scripts send, printing each candidate as
+// "value\thelp" and then the directive line. For a directive filtering files by extension, the
+// candidates are the extensions.
 func (c *app) handleComplete(args []string) {
+	candidates, directive := c.complete(args)
+	for _, candidate := range candidates {
+		fmt.Fprintf(c.stdio().Out, "%s\t%s\n", candidate.Value, candidate.Help)
+	}
+
+	fmt.Fprintf(c.stdio().Out, ":%d\n", directive)
+}
+
+// Complete returns the candidates for the last of args, the word being completed (possibly ""),
+// given the words before it: the flags of the deepest matched command when the word starts with "-",
+// the value of a flag after "--flag " or "--flag=", and otherwise its subcommand names and the
+// values of the positional at that spot. A value left to the shell's path completion (see tagComplete)
+// has no candidates here.
+func (c *app) Complete(args []string) []Completion {
+	candidates, directive := c.complete(args)
+	if directive&shellCompDirectiveFilterFileExt != 0 {
+		return nil
+	}
+
+	return candidates
+}
+
+// complete is Complete with the directive for the shell: no file completion, unless the value being
+// completed is a path (see pathDirective).
+func (c *app) complete(args []string) ([]Completion, int) {
 	toComplete := ""
 	if len(args) > 0 {
 		toComplete = args[len(args)-1]
 		args = args[:len(args)-1]
 	}
 
-	// walk args to find the deepest matching command
-	commands := c.commands
-	var matched Command[any]
+	// walk args to find the deepest matching command, the positionals after it, and whether the
+	// last word is a flag still waiting for its value
+	commands := c.Commands()
+	var chain []Command[any]
+	var pending *structs.Field
+	pendingName := ""
+	position := 0
 	for _, arg := range args {
-		if strings.HasPrefix(arg, "-") {
+		// as the parser does, a flag waiting for its value takes the next word only when it is not a flag
+		waiting := pending != nil
+		pending = nil
+		switch {
+		case waiting && !strings.HasPrefix(arg, optionPrefix):
+			continue
+		case arg == argsTerminator:
 			continue
+		case strings.HasPrefix(arg, optionPrefix):
+			name := strings.TrimLeft(arg, optionPrefix)
+			if field := matchField(c.completionFields(chain), name); field != nil && takesValue(*field) {
+				pending, pendingName = field, valueFlagName(*field, name)
+			}
+		default:
+			if position == 0 {
+				if found := c.matchCommandByName(arg, commands); found != nil {
+					chain = append(chain, found)
+					commands = found.Commands()
+					continue
+				}
+			}
+			position++
+		}
+	}
+
+	if strings.HasPrefix(toComplete, optionPrefix) {
+		if flag, prefix, ok := strings.Cut(toComplete, "="); ok {
+			name := strings.TrimLeft(flag, optionPrefix)
+			field := matchField(c.completionFields(chain), name)
+			if field == nil {
+				return nil, shellCompDirectiveNoFileComp
+			}
+			if directive, exts, ok := pathDirective(*field); ok {
+				return exts, directive
+			}
+			req := CompletionRequest{Flag: valueFlagName(*field, name), Position: -1, Prefix: prefix}
+			return withValuePrefix(c.completeValues(chain, field, req), flag+"="), shellCompDirectiveNoFileComp
 		}
-		found := c.matchCommandByName(arg, commands)
-		if found == nil {
-			break
+		return c.completeFlagNames(chain, strings.TrimLeft(toComplete, optionPrefix)), shellCompDirectiveNoFileComp
+	}
+	if pending != nil {
+		if directive, exts, ok := pathDirective(*pending); ok {
+			return exts, directive
 		}
-		matched = found
-		commands = found.Commands()
+		return c.completeValues(chain, pending, CompletionRequest{Flag: pendingName, Position: -1, Prefix: toComplete}), shellCompDirectiveNoFileComp
 	}
 
-	if strings.HasPrefix(toComplete, "-") {
-		prefix := strings.TrimLeft(toComplete, "-")
-		c.completeFlagNames(matched, prefix)
-	} else {
+	var positional *structs.Field
+	if len(chain) > 0 {
+		positional = matchField(c.completionFields(chain), strconv.Itoa(position))
+	}
+	if positional != nil && (position > 0 || len(commands) == 0) {
+		if directive, exts, ok := pathDirective(*positional); ok {
+			return exts, directive
+		}
+	}
+
+	var candidates []Completion
+	if position == 0 {
 		for _, cmd := range commands {
+			if CommandHidden(cmd) || CommandDeprecation(cmd).IsDeprecated() {
+				continue
+			}
 			name := cmd.Name("")
 			if strings.HasPrefix(name, toComplete) {
-				fmt.Fprintf(os.Stdout, "%s\t%s\n", name, cmd.Help())
+				candidates = append(candidates, Completion{Value: name, Help: cmd.Help()})
+			}
+		}
+	}
+	if len(chain) > 0 {
+		candidates = append(candidates, c.completeValues(chain, positional, CompletionRequest{Position: position, Prefix: toComplete})...)
+	}
+
+	return candidates, shellCompDirectiveNoFileComp
+}
+
+// pathDirective is the directive for completing field's value as a path, from its complete tag (see
+// tagComplete), with the extensions to filter by as candidates; false when the field has none.
+func pathDirective(field structs.Field) (int, []Completion, bool) {
+	kind, filter, _ := strings.Cut(field.Tags[tagComplete], ":")
+	switch kind {
+	case "file":
+		var exts []Completion
+		for _, ext := range splitList(filter) {
+			if ext = strings.TrimPrefix(ext, "."); ext != "" {
+				exts = append(exts, Completion{Value: ext})
+			}
+		}
+		if len(exts) == 0 {
+			return 0, nil, true
+		}
+		return shellCompDirectiveFilterFileExt, exts, true
+	case "dir":
+		return shellCompDirectiveFilterDirs | shellCompDirectiveNoSpace, nil, true
+	}
+
+	return 0, nil, false
+}
+
+// completionFields are the fields of the flags the last command in chain accepts: its own, then those
+// inherited from its ancestors (nearest first, see Persistent), then the globals.
+func (c *app) completionFields(chain []Command[any]) []structs.Field {
+	var fields []structs.Field
+	add := func(options any) {
+		if options == nil {
+			return
+		}
+		if more, err := structs.GetStructFields(options, nil, structs.DefaultEncodingTags); err == nil {
+			fields = append(fields, more...)
+		}
+	}
+	if len(chain) > 0 {
+		add(chain[len(chain)-1].Options())
+		for i := len(chain) - 2; i >= 0; i-- {
+			if p, ok := chain[i].(Persistent); ok {
+				add(p.PersistentOptions())
 			}
 		}
 	}
+	add(c.globalFlags)
+
+	return fields
+}
+
+// takesValue reports whether field's flag reads the next word as its value: not a bool or a counter,
+// which stand alone, nor a struct, whose nested fields are the flags, unless it is parsed from text.
+func takesValue(field structs.Field) bool {
+	if field.Type == "bool" || FieldCounter(field) {
+		return false
+	}
+
+	return len(field.Fields) == 0 || (field.Value.IsValid() && isTextType(field.Value.Type()))
+}
+
+// completeValues lists the values for req starting with its prefix: field's oneof values, or true and
+// false for a bool, then whatever the last command in chain suggests as a Completer. field is nil for
+// a positional the command has no field for.
+func (c *app) completeValues(chain []Command[any], field *structs.Field, req CompletionRequest) []Completion {
+	var values []string
+	if field != nil {
+		for _, rule := range field.Rules {
+			if rule.Name == "oneof" {
+				values = rule.Args
+			}
+		}
+		if field.Type == "bool" {
+			values = []string{"true", "false"}
+		}
+	}
+
+	var candidates []Completion
+	for _, value := range values {
+		if strings.HasPrefix(value, req.Prefix) {
+			candidates = append(candidates, Completion{Value: value})
+		}
+	}
+	if len(chain) > 0 {
+		if completer, ok := chain[len(chain)-1].(Completer); ok {
+			candidates = append(candidates, completer.CompleteValues(req)...)
+		}
+	}
+
+	return candidates
+}
+
+// valueFlagName is the name a CompletionRequest gives field's flag, typed as name: its long name, so
+// "-c" and "--cluster" ask for the same values.
+func valueFlagName(field structs.Field, name string) string {
+	if long := completionName(field); long != "" {
+		return long
+	}
 
-	fmt.Fprintf(os.Stdout, ":%d\n", shellCompDirectiveNoFileComp)
+	return name
 }
 
-func (c *app) completeFlagNames(cmd Command[any], prefix string) {
+// withValuePrefix puts prefix ("--format=") in front of each candidate, so the shell matches them
+// against the whole word being completed.
+func withValuePrefix(candidates []Completion, prefix string) []Completion {
+	for i := range candidates {
+		candidates[i].Value = prefix + candidates[i].Value
+	}
+
+	return candidates
+}
+
+// completeFlagNames lists the flags starting with prefix that the last command in chain accepts:
+// its own, then those inherited from its ancestors (nearest first, see Persistent), then the globals.
+func (c *app) completeFlagNames(chain []Command[any], prefix string) []Completion {
 	seen := make(map[string]bool)
 
-	if cmd != nil {
-		c.completeFlagsFromOptions(cmd.Options(), prefix, seen)
+	var candidates []Completion
+	if len(chain) > 0 {
+		candidates = completeFlagsFromOptions(candidates, chain[len(chain)-1].Options(), prefix, seen)
+		for i := len(chain) - 2; i >= 0; i-- {
+			if p, ok := chain[i].(Persistent); ok {
+				candidates = completeFlagsFromOptions(candidates, p.PersistentOptions(), prefix, seen)
+			}
+		}
 	}
-	c.completeFlagsFromOptions(c.globalFlags, prefix, seen)
+
+	return completeFlagsFromOptions(candidates, c.globalFlags, prefix, seen)
 }
 
-func (c *app) completeFlagsFromOptions(options any, prefix string, seen map[string]bool) {
+// completeFlagsFromOptions appends the flags of options starting with prefix, skipping those in seen.
+// A nested struct's flags are offered by their dotted names ("--database.host"), and a bool flag whose
+// negation is advertised (see FieldNegatable) is offered as "--no-<name>" too.
+func completeFlagsFromOptions(candidates []Completion, options any, prefix string, seen map[string]bool) []Completion {
 	if options == nil {
-		return
+		return candidates
 	}
 
 	fields, err := structs.GetStructFields(options, nil, structs.DefaultEncodingTags)
 	if err != nil {
-		return
+		return candidates
 	}
 
-	for _, field := range fields {
-		name := field.Tags["arg"]
-		if name == "" {
-			continue
+	walkFields(fields, "", func(field structs.Field, _ string) {
+		name := completionName(field)
+		if name == "" || isPositional(name) {
+			return
 		}
-		if seen[name] {
-			continue
+		if seen[name] || FieldDeprecation(field).IsDeprecated() {
+			return
 		}
 		if strings.HasPrefix(name, prefix) {
 			seen[name] = true
-			fmt.Fprintf(os.Stdout, "--%s\t%s\n", name, field.Tags["help"])
+			candidates = append(candidates, Completion{Value: "--" + name, Help: field.Tags["help"]})
+		}
+		if negated := negationPrefix + name; FieldNegatable(field) && !seen[negated] && strings.HasPrefix(negated, prefix) {
+			seen[negated] = true
+			candidates = append(candidates, Completion{Value: "--" + negated, Help: field.Tags["help"]})
+		}
+	})
+
+	return candidates
+}
+
+// completionName is the flag name offered for field: its arg, dotted for a nested field, or for a nested
+// field without one the dotted name of its first encoding tag, which the parser accepts as well
+// ("--database.host" from `json:"host"`; see matchNestedField).
+func completionName(field structs.Field) string {
+	if name := fieldTag(field, tagArg); name != "" || field.FQN == nil {
+		return name
+	}
+	for _, tag := range structs.DefaultEncodingTags {
+		if tag == tagArg || tag == tagShort || tag == "env" {
+			continue
+		}
+		if name := field.FQN.Tags[tag]; name != "" {
+			return name
 		}
 	}
+
+	return ""
 }
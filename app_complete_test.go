@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+)
+
+type completeDB struct {
+	Host string `json:"host" help:"database host"`
+	Port int    `json:"port"`
+}
+
+type completeConfig struct {
+	Target   string     `arg:"0"`
+	Cluster  string     `arg:"cluster" short:"c" help:"cluster to deploy to"`
+	Format   string     `arg:"format" rules:"oneof:json,yaml,table"`
+	DryRun   bool       `arg:"dry-run"`
+	Database completeDB `arg:"database" json:"database"`
+	Config   string     `arg:"config" complete:"file:.yaml,yml"`
+	Output   string     `arg:"output" complete:"file"`
+	Workdir  string     `arg:"workdir" complete:"dir"`
+	Endpoint url.URL    `arg:"endpoint"`
+}
+
+type completeCommand struct {
+	BaseCommand[completeConfig]
+	requests []CompletionRequest
+}
+
+func (c *completeCommand) Help() string                        { return "deploy" }
+func (c *completeCommand) Run(_ GlobalFlags, _ Unknowns) error { return nil }
+
+func (c *completeCommand) CompleteValues(req CompletionRequest) []Completion {
+	c.requests = append(c.requests, req)
+	switch {
+	case req.Flag == "cluster":
+		return []Completion{{Value: "prod", Help: "production"}, {Value: "staging"}}
+	case req.Flag == "" && req.Position == 0:
+		return []Completion{{Value: "web"}, {Value: "worker"}}
+	}
+	return nil
+}
+
+func completeValues(app *app, args ...string) []string {
+	var got []string
+	for _, c := range app.Complete(args) {
+		got = append(got, c.Value)
+	}
+	return got
+}
+
+// completeOutput returns what the hidden __complete command prints for args, read from the app's Out.
+func completeOutput(app *app, args ...string) string {
+	var out bytes.Buffer
+	streams := app.streams
+	streams.Out = &out
+	app.IO(streams)
+	app.handleComplete(args)
+
+	return out.String()
+}
+
+func Test_App_Complete_Values(t *testing.T) {
+	cmd := &completeCommand{BaseCommand: NewBaseCommand[completeConfig]()}
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.Add("deploy", cmd)
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "oneof after the flag", args: []string{"deploy", "--format", ""}, want: []string{"json", "yaml", "table"}},
+		{name: "oneof by prefix", args: []string{"deploy", "--format", "y"}, want: []string{"yaml"}},
+		{name: "oneof after =", args: []string{"deploy", "--format=t"}, want: []string{"--format=table"}},
+		{name: "bool after =", args: []string{"deploy", "--dry-run="}, want: []string{"--dry-run=true", "--dry-run=false"}},
+		{name: "a bool takes no value", args: []string{"deploy", "--dry-run", ""}, want: []string{"web", "worker"}},
+		{name: "completer after the flag", args: []string{"deploy", "--cluster", ""}, want: []string{"prod", "staging"}},
+		{name: "completer after the short", args: []string{"deploy", "-c", "p"}, want: []string{"prod", "staging"}},
+		{name: "completer after =", args: []string{"deploy", "--cluster="}, want: []string{"--cluster=prod", "--cluster=staging"}},
+		{name: "positional", args: []string{"deploy", "--cluster", "prod", "w"}, want: []string{"web", "worker"}},
+		{name: "second positional", args: []string{"deploy", "web", ""}, want: nil},
+		{name: "a text struct takes a value", args: []string{"deploy", "--endpoint", ""}, want: nil},
+		{name: "a text struct's value is not a positional", args: []string{"deploy", "--endpoint", "http://x", ""}, want: []string{"web", "worker"}},
+		{name: "a flag is not a value", args: []string{"deploy", "--cluster", "--format", ""}, want: []string{"json", "yaml", "table"}},
+		{name: "unknown flag", args: []string{"deploy", "--nope="}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, tt.want, completeValues(app, tt.args...))
+		})
+	}
+}
+
+func Test_App_Complete_Values_Request(t *testing.T) {
+	cmd := &completeCommand{BaseCommand: NewBaseCommand[completeConfig]()}
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.Add("deploy", cmd)
+
+	app.Complete([]string{"deploy", "-c", "pr"})
+	app.Complete([]string{"deploy", "--database.host=db"})
+	app.Complete([]string{"deploy", "web", "x"})
+	assertEqual(t, []CompletionRequest{
+		{Flag: "cluster", Position: -1, Prefix: "pr"},
+		{Flag: "database.host", Position: -1, Prefix: "db"},
+		{Position: 1, Prefix: "x"},
+	}, cmd.requests)
+}
+
+func Test_App_Complete_NestedFlags(t *testing.T) {
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.Add("deploy", &completeCommand{BaseCommand: NewBaseCommand[completeConfig]()})
+
+	assertEqual(t, []string{"--database.host", "--database.port"}, completeValues(app, "deploy", "--data"))
+	assertEqual(t, []string{"--dry-run"}, completeValues(app, "deploy", "--dr"))
+}
+
+func Test_App_Complete_ValueDirective(t *testing.T) {
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.Add("deploy", &completeCommand{BaseCommand: NewBaseCommand[completeConfig]()})
+
+	assertEqual(t, "prod\tproduction\nstaging\t\n:4\n", completeOutput(app, "deploy", "--cluster", ""))
+}
+
+func Test_App_Complete_PathDirectives(t *testing.T) {
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.Add("deploy", &completeCommand{BaseCommand: NewBaseCommand[completeConfig]()})
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "extensions after the flag", args: []string{"deploy", "--config", ""}, want: "yaml\t\nyml\t\n:8\n"},
+		{name: "extensions after =", args: []string{"deploy", "--config=conf/"}, want: "yaml\t\nyml\t\n:8\n"},
+		{name: "any file", args: []string{"deploy", "--output", "out"}, want: ":0\n"},
+		{name: "directories", args: []string{"deploy", "--workdir="}, want: ":18\n"},
+		{name: "other values", args: []string{"deploy", "--format", ""}, want: "json\t\nyaml\t\ntable\t\n:4\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, tt.want, completeOutput(app, tt.args...))
+		})
+	}
+
+	assertEqual(t, []string(nil), completeValues(app, "deploy", "--config", ""), "Complete leaves paths to the shell")
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"slices"
@@ -30,26 +31,28 @@ func (c *app) getGlobalFlags(osArgs []string) (map[string]any, map[string]any) {
 // The parser lets a value-taking flag (a command's own "--target", or any flag unknown
 // to the global scan) swallow a following "--help" as its value, so a direct scan is
 // what makes built-in bool flags like -h/--help and -V/--version trigger no matter
-// where they sit. Scanning stops at a "--" terminator, and the explicit "--flag=false"
-// form does not count as set.
+// where they sit. Scanning stops at a "--" terminator. The last mention wins, the way the
+// parser's does: the explicit "--flag=false" form and the negated "--no-flag" both unset it.
 func boolFlagRequested(osArgs []string, names []string) bool {
+	requested := false
 	for _, raw := range osArgs {
-		if raw == "--" {
+		if raw == argsTerminator {
 			break
 		}
 		if !strings.HasPrefix(raw, optionPrefix) {
 			continue
 		}
 		name, value := splitKeyValue(strings.TrimLeft(raw, optionPrefix))
-		if !slices.Contains(names, name) {
+		hasValue := strings.Contains(raw, "=")
+		if slices.Contains(names, name) {
+			requested = !hasValue || truthy(value)
 			continue
 		}
-		if strings.Contains(raw, "=") && !truthy(value) {
-			continue
+		if flag, ok := strings.CutPrefix(name, negationPrefix); ok && slices.Contains(names, flag) {
+			requested = hasValue && !truthy(value)
 		}
-		return true
 	}
-	return false
+	return requested
 }
 
 // globalBoolFlagNames returns the long and short spellings of the GlobalFlags field
@@ -162,10 +165,10 @@ func builtinFormatValues() []string {
 }
 
 func (c *app) printVersion() {
-	fmt.Printf("%s %s\n", c.config.Name, c.config.Version)
+	fmt.Fprintf(c.stdio().Out, "%s %s\n", c.config.Name, c.config.Version)
 }
 
-func (c *app) runHelp(args []string, opts ...map[string]any) error {
+func (c *app) runHelp(ctx context.Context, args []string, opts ...map[string]any) error {
 	options := map[string]any{}
 	if len(opts) > 0 && opts[0] != nil {
 		options = opts[0]
@@ -173,7 +176,7 @@ func (c *app) runHelp(args []string, opts ...map[string]any) error {
 
 	for _, cmd := range c.commands {
 		if cmd.Name("") == helpCommand {
-			err := cmd.Run(*c.globalFlags, Unknowns{
+			err := runCommand(ctx, c.stdio(), cmd, *c.globalFlags, Unknowns{
 				Args:    args,
 				Options: options,
 			})
@@ -186,3 +189,20 @@ func (c *app) runHelp(args []string, opts ...map[string]any) error {
 
 	return errors.New("help command not found")
 }
+
+// helpCauseKey is the context key under which the error that diverted a run to help is stored.
+type helpCauseKey struct{}
+
+// withHelpCause returns ctx carrying err as the reason help is being shown instead of a command.
+func withHelpCause(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, helpCauseKey{}, err)
+}
+
+// HelpCause returns the error that made the App show help instead of running a command,
+// such as an *UnknownCommandError for a mistyped command name, or nil when help was requested
+// (--help, the help command, or a bare invocation). The help command reads it from the context
+// it runs under (see ContextRunner) to report the failure alongside the help output.
+func HelpCause(ctx context.Context) error {
+	err, _ := ctx.Value(helpCauseKey{}).(error)
+	return err
+}
@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrInterrupted is the cancellation cause of the context Run hands to commands
+// once the process receives SIGINT or SIGTERM. Read it with context.Cause(ctx).
+var ErrInterrupted = errors.New("interrupted")
+
+// signalCause is the cancellation cause runSignals.watch records: ErrInterrupted, naming the signal received.
+type signalCause struct {
+	sig os.Signal
+}
+
+func (e signalCause) Error() string { return fmt.Sprintf("%s: %s", ErrInterrupted, e.sig) }
+func (e signalCause) Unwrap() error { return ErrInterrupted }
+
+// interruptedBy reports the signal that cancelled ctx, or nil when it was not cancelled by one.
+func interruptedBy(ctx context.Context) os.Signal {
+	var cause signalCause
+	if errors.As(context.Cause(ctx), &cause) {
+		return cause.sig
+	}
+
+	return nil
+}
+
+// runSignals is the signal state of one Run, carried by its context so Apps sharing a process never
+// see each other's: the funcs registered with onSignal, and the live InterruptContext scopes.
+type runSignals struct {
+	mu     sync.Mutex
+	next   int
+	hooks  map[int]func()
+	scopes []*interruptScope
+}
+
+// runSignalsKey is the context key of a Run's *runSignals.
+type runSignalsKey struct{}
+
+// signalsOf returns the signal state of the Run ctx belongs to, nil outside of Run.
+func signalsOf(ctx context.Context) *runSignals {
+	s, _ := ctx.Value(runSignalsKey{}).(*runSignals)
+
+	return s
+}
+
+// onSignal registers fn to run on each shutdown signal, before anything is cancelled, and again before
+// a force exit. It undoes a terminal change, such as echo turned off, that a blocked read would leave
+// behind, so fn must be safe to call more than once. The returned func unregisters fn.
+func (s *runSignals) onSignal(fn func()) func() {
+	if s == nil {
+		return func() {}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.hooks == nil {
+		s.hooks = make(map[int]func())
+	}
+	id := s.next
+	s.next++
+	s.hooks[id] = fn
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.hooks, id)
+	}
+}
+
+// runHooks runs every func registered with onSignal.
+func (s *runSignals) runHooks() {
+	s.mu.Lock()
+	funcs := make([]func(), 0, len(s.hooks))
+	for _, fn := range s.hooks {
+		funcs = append(funcs, fn)
+	}
+	s.mu.Unlock()
+
+	for _, fn := range funcs {
+		fn()
+	}
+}
+
+// interruptScope is a live context made with InterruptContext.
+type interruptScope struct {
+	cancel context.CancelCauseFunc
+}
+
+// InterruptContext returns a child of ctx that the next Ctrl-C (SIGINT) under Run cancels in place of the
+// run context, with ErrInterrupted as the cause. It is for a command that runs work in steps, as a shell
+// runs lines, and wants Ctrl-C to stop the step in progress rather than the command itself. The context
+// takes one SIGINT; the next goes to the run context as usual, and SIGTERM always does. Call the returned
+// func once the step has ended. Outside of Run, it is a plain cancellable child of ctx.
+func InterruptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	s := signalsOf(ctx)
+	if s == nil {
+		return ctx, func() { cancel(nil) }
+	}
+	scope := &interruptScope{cancel: cancel}
+
+	s.mu.Lock()
+	s.scopes = append(s.scopes, scope)
+	s.mu.Unlock()
+
+	return ctx, func() {
+		s.mu.Lock()
+		s.scopes = slices.DeleteFunc(s.scopes, func(other *interruptScope) bool { return other == scope })
+		s.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// takeInterrupt cancels and releases the innermost InterruptContext with sig, and reports whether there
+// was one to take it. Only SIGINT is taken.
+func (s *runSignals) takeInterrupt(sig os.Signal) bool {
+	if sig != os.Interrupt {
+		return false
+	}
+	s.mu.Lock()
+	n := len(s.scopes)
+	if n == 0 {
+		s.mu.Unlock()
+		return false
+	}
+	scope := s.scopes[n-1]
+	s.scopes = s.scopes[:n-1]
+	s.mu.Unlock()
+
+	scope.cancel(signalCause{sig: sig})
+
+	return true
+}
+
+// shutdownSignals are the signals that cancel the run context: Ctrl-C and the
+// polite termination request sent by process supervisors (systemd, docker, k8s).
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// GracePeriod sets how long Run waits for the command to return after the first
+// interrupt before force-exiting, and returns the app for chaining. Zero (the default)
+// waits indefinitely; a second interrupt force-exits either way.
+func (c *app) GracePeriod(timeout time.Duration) App {
+	c.grace = timeout
+
+	return c
+}
+
+// signalContext derives a context from parent that the framework owns on the command's behalf:
+// the first SIGINT/SIGTERM cancels it (with ErrInterrupted as the cause), so a command blocked on
+// ctx.Done() can shut down cleanly; a second signal, or the grace period running out, force-exits
+// the process with the conventional 128+signal status. The context carries the run's own signal
+// state (see onSignal, InterruptContext). The returned stop func releases the signal handler and
+// must be called once the command has returned.
+func (c *app) signalContext(parent context.Context) (context.Context, context.CancelFunc) {
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, shutdownSignals...)
+
+	state := &runSignals{}
+	ctx, cancel := context.WithCancelCause(context.WithValue(parent, runSignalsKey{}, state))
+	done := make(chan struct{})
+	go state.watch(sigs, done, cancel, c.grace, c.exitProcess)
+
+	return ctx, func() {
+		signal.Stop(sigs)
+		close(done)
+		cancel(nil)
+	}
+}
+
+// watch cancels the run on the first signal from sigs that no InterruptContext takes, then calls exit
+// on a second one or once grace elapses (when non-zero). It returns when done is closed, i.e. the
+// command returned.
+func (s *runSignals) watch(sigs <-chan os.Signal, done <-chan struct{}, cancel context.CancelCauseFunc, grace time.Duration, exit func(code int)) {
+	var sig os.Signal
+	for sig == nil {
+		select {
+		case got := <-sigs:
+			s.runHooks()
+			if !s.takeInterrupt(got) {
+				sig = got
+			}
+		case <-done:
+			return
+		}
+	}
+	cancel(signalCause{sig: sig})
+
+	var deadline <-chan time.Time
+	if grace > 0 {
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	select {
+	case sig = <-sigs:
+	case <-deadline:
+	case <-done:
+		return
+	}
+	s.runHooks()
+	exit(signalExitCode(sig))
+}
+
+// exitProcess ends the process with code: os.Exit, unless a test swapped in its own exit func.
+func (c *app) exitProcess(code int) {
+	if c.exit != nil {
+		c.exit(code)
+		return
+	}
+	os.Exit(code)
+}
+
+// signalExitCode is the shell convention for a process ended by a signal: 128 plus the signal number
+// (130 for SIGINT, 143 for SIGTERM). Signals without a number fall back to 1.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
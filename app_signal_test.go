@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type ctxConfig struct{}
+
+// ctxCommand records the context it ran under, through the BaseCommand accessor.
+type ctxCommand struct {
+	BaseCommand[ctxConfig]
+	got context.Context
+}
+
+var _ Command[ctxConfig] = (*ctxCommand)(nil)
+
+func (c *ctxCommand) Help() string { return "ctx" }
+func (c *ctxCommand) Run(_ GlobalFlags, _ Unknowns) error {
+	c.got = c.Context()
+	return nil
+}
+
+// ctxRunner implements ContextRunner, so RunContext is preferred over Run.
+type ctxRunner struct {
+	BaseCommand[ctxConfig]
+	got    context.Context
+	ranRun bool
+}
+
+var _ Command[ctxConfig] = (*ctxRunner)(nil)
+var _ ContextRunner = (*ctxRunner)(nil)
+
+func (c *ctxRunner) Help() string { return "ctx runner" }
+func (c *ctxRunner) Run(_ GlobalFlags, _ Unknowns) error {
+	c.ranRun = true
+	return nil
+}
+func (c *ctxRunner) RunContext(ctx context.Context, _ GlobalFlags, _ Unknowns) error {
+	c.got = ctx
+	return ctx.Err()
+}
+
+type ctxKey struct{}
+
+func Test_App_RunContext_BindsBaseCommandContext(t *testing.T) {
+	app := newTestApp(Config{}, GlobalFlags{})
+	cmd := &ctxCommand{BaseCommand: NewBaseCommand[ctxConfig]()}
+	app.Add("run", cmd)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	assertNoError(t, app.RunContext(ctx, []string{"run"}))
+	assertEqual(t, "v", cmd.got.Value(ctxKey{}))
+}
+
+func Test_App_RunContext_PrefersContextRunner(t *testing.T) {
+	app := newTestApp(Config{}, GlobalFlags{})
+	cmd := &ctxRunner{BaseCommand: NewBaseCommand[ctxConfig]()}
+	app.Add("run", cmd)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := app.RunContext(ctx, []string{"run"})
+	assertErrorIs(t, err, context.Canceled)
+	assertEqual(t, false, cmd.ranRun)
+	assertEqual(t, ctx, cmd.got)
+}
+
+func Test_App_Run_ProvidesSignalContext(t *testing.T) {
+	app := newTestApp(Config{}, GlobalFlags{})
+	cmd := &ctxCommand{BaseCommand: NewBaseCommand[ctxConfig]()}
+	app.Add("run", cmd)
+
+	assertNoError(t, app.Run([]string{"run"}))
+	assertNotNil(t, cmd.got)
+	// the run context is released once the command returns.
+	<-cmd.got.Done()
+}
+
+func Test_BaseCommand_Context_DefaultsToBackground(t *testing.T) {
+	cmd := NewBaseCommand[ctxConfig]()
+	assertEqual(t, context.Background(), cmd.Context())
+}
+
+func Test_runSignals_watch(t *testing.T) {
+	tests := []struct {
+		name     string
+		signals  []os.Signal
+		grace    time.Duration
+		wantExit int
+		// wantHooks is how many times the onSignal hooks ran: on the first signal, and before a force exit.
+		wantHooks int32
+	}{
+		{name: "first signal cancels only", signals: []os.Signal{syscall.SIGINT}, wantExit: -1, wantHooks: 1},
+		{name: "second signal force-exits", signals: []os.Signal{syscall.SIGINT, syscall.SIGINT}, wantExit: 130, wantHooks: 2},
+		{name: "grace timeout force-exits", signals: []os.Signal{syscall.SIGTERM}, grace: time.Millisecond, wantExit: 143, wantHooks: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			exited := make(chan int, 1)
+			state := &runSignals{}
+			var hooks atomic.Int32
+			state.onSignal(func() { hooks.Add(1) })
+
+			sigs := make(chan os.Signal, len(tt.signals))
+			for _, sig := range tt.signals {
+				sigs <- sig
+			}
+			ctx, cancel := context.WithCancelCause(context.Background())
+			done := make(chan struct{})
+			returned := make(chan struct{})
+			go func() {
+				state.watch(sigs, done, cancel, tt.grace, func(code int) { exited <- code })
+				close(returned)
+			}()
+
+			<-ctx.Done()
+			if !errors.Is(context.Cause(ctx), ErrInterrupted) {
+				t.Fatalf("expected ErrInterrupted cause, got %v", context.Cause(ctx))
+			}
+
+			if tt.wantExit < 0 {
+				close(done)
+				<-returned
+				select {
+				case code := <-exited:
+					t.Fatalf("unexpected exit %d", code)
+				default:
+				}
+				assertEqual(t, tt.wantHooks, hooks.Load())
+				return
+			}
+			assertEqual(t, tt.wantExit, <-exited)
+			<-returned
+			assertEqual(t, tt.wantHooks, hooks.Load())
+		})
+	}
+}
+
+func Test_runSignals_InterruptContext(t *testing.T) {
+	noExit := func(code int) { t.Errorf("unexpected exit %d", code) }
+	state := &runSignals{}
+	withState := func(parent context.Context) context.Context {
+		return context.WithValue(parent, runSignalsKey{}, state)
+	}
+
+	sigs := make(chan os.Signal, 1)
+	ctx, cancel := context.WithCancelCause(withState(context.Background()))
+	done := make(chan struct{})
+	returned := make(chan struct{})
+	go func() {
+		state.watch(sigs, done, cancel, 0, noExit)
+		close(returned)
+	}()
+
+	// a step not under the run context, so only being taken would cancel it
+	step, stop := InterruptContext(withState(context.Background()))
+	sigs <- syscall.SIGTERM
+	<-ctx.Done()
+	assertEqual(t, nil, step.Err(), "SIGTERM is not taken by an InterruptContext")
+	stop()
+	close(done)
+	<-returned
+
+	ctx, cancel = context.WithCancelCause(withState(context.Background()))
+	done = make(chan struct{})
+	returned = make(chan struct{})
+	go func() {
+		state.watch(sigs, done, cancel, 0, noExit)
+		close(returned)
+	}()
+
+	step, stop = InterruptContext(ctx)
+	sigs <- syscall.SIGINT
+	<-step.Done()
+	assertEqual(t, os.Interrupt, interruptedBy(step))
+	assertEqual(t, nil, ctx.Err(), "the run context outlives an interrupted step")
+	stop()
+
+	sigs <- syscall.SIGINT
+	<-ctx.Done()
+	assertErrorIs(t, context.Cause(ctx), ErrInterrupted, "the next SIGINT goes to the run context")
+	close(done)
+	<-returned
+}
+
+func Test_runSignals_PerRun(t *testing.T) {
+	one, two := &runSignals{}, &runSignals{}
+	var ran atomic.Int32
+	release := one.onSignal(func() { ran.Add(1) })
+
+	two.runHooks()
+	assertEqual(t, int32(0), ran.Load(), "another run's hooks are not run")
+	one.runHooks()
+	assertEqual(t, int32(1), ran.Load())
+	release()
+	one.runHooks()
+	assertEqual(t, int32(1), ran.Load(), "a released hook is not run")
+
+	step, stop := InterruptContext(context.Background())
+	defer stop()
+	assertEqual(t, true, signalsOf(step) == nil, "outside of Run there is no signal state")
+	assertEqual(t, false, two.takeInterrupt(os.Interrupt), "a run without steps takes no interrupt")
+}
@@ -286,6 +286,10 @@ func Test_boolFlagRequested(t *testing.T) {
 		{"after the -- terminator", []string{"--", "--help"}, false},
 		{"name only as a flag value", []string{"--message=--help"}, false},
 		{"name as a positional", []string{"help"}, false},
+		{"negated", []string{"--no-help"}, false},
+		{"negated after set", []string{"--help", "--no-help"}, false},
+		{"set after negated", []string{"--no-help", "-h"}, true},
+		{"negated false", []string{"--no-help=false"}, true},
 	}
 
 	for _, tt := range tests {
@@ -554,6 +558,47 @@ func newPositionalCommand(run func() error) *PositionalCommand {
 	return &PositionalCommand{run: run, BaseCommand: NewBaseCommand[PositionalCommandConfig]()}
 }
 
+// unknownsCommand records the Unknowns it runs with.
+type unknownsCommand struct {
+	BaseCommand[PositionalCommandConfig]
+	got Unknowns
+}
+
+func (m *unknownsCommand) Help() string { return "exec" }
+func (m *unknownsCommand) Run(_ GlobalFlags, unknowns Unknowns) error {
+	m.got = unknowns
+	return nil
+}
+
+func Test_App_Passthrough(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		target      string
+		passthrough []string
+	}{
+		{name: "after the terminator, verbatim and in order", args: []string{"exec", "box", "--", "ls", "-la", "--color", "help"}, target: "box", passthrough: []string{"ls", "-la", "--color", "help"}},
+		{name: "never fills a positional", args: []string{"exec", "--", "box"}, passthrough: []string{"box"}},
+		{name: "a second terminator is passed on", args: []string{"exec", "--", "--", "-x"}, passthrough: []string{"--", "-x"}},
+		{name: "none without a terminator", args: []string{"exec", "box"}, target: "box"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &unknownsCommand{BaseCommand: NewBaseCommand[PositionalCommandConfig]()}
+			app := newTestApp(Config{}, GlobalFlags{})
+			app.Add("help", NewMockCommand(func() error { t.Fatal("help ran"); return nil }))
+			app.Add("exec", cmd)
+
+			assertNoError(t, app.Run(tt.args))
+			assertEqual(t, tt.target, cmd.Inputs.Target)
+			assertEqual(t, strings.Join(tt.passthrough, " "), strings.Join(cmd.got.Passthrough, " "))
+			assertLen(t, cmd.got.Options, 0, "no passthrough arg is parsed as a flag")
+			assertLen(t, cmd.got.Args, 0)
+		})
+	}
+}
+
 // when the default command declares a positional arg, a leading bare token is that
 // positional and runs the default; a second, undeclared bare token is unknown.
 func Test_App_DefaultCommand_WithPositional(t *testing.T) {
@@ -750,6 +795,54 @@ func Test_App_MatchCommandByName(t *testing.T) {
 	}
 }
 
+func Test_App_MatchCommandByName_Aliases(t *testing.T) {
+	app := newTestApp(Config{}, GlobalFlags{})
+	remove := NewMockCommand(nil)
+	remove.Alias("rm", "list")
+	list := NewMockCommand(nil)
+	list.Alias("ls")
+	app.Add("remove", remove)
+	app.Add("list", list)
+
+	assertEqual(t, "remove", app.matchCommandByName("rm", app.commands).Name(""), "alias dispatches")
+	assertEqual(t, "list", app.matchCommandByName("ls", app.commands).Name(""), "alias dispatches")
+	assertEqual(t, "list", app.matchCommandByName("list", app.commands).Name(""), "a name beats a sibling's alias")
+}
+
+func Test_App_Run_ByAliasAndHidden(t *testing.T) {
+	ran := ""
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.Add("help", NewMockCommand(func() error { return nil }))
+	db := NewMockCommand(func() error { return nil })
+	db.Alias("d")
+	migrate := NewMockCommand(func() error { ran = "migrate"; return nil })
+	migrate.Alias("m")
+	internal := NewMockCommand(func() error { ran = "internal"; return nil })
+	internal.Hide()
+	app.Add("db", db).Add("migrate", migrate)
+	app.Add("internal", internal)
+
+	assertNoError(t, app.Run([]string{"d", "m"}))
+	assertEqual(t, "migrate", ran)
+
+	assertNoError(t, app.Run([]string{"internal"}), "a hidden command still runs")
+	assertEqual(t, "internal", ran)
+}
+
+func Test_App_Complete_SkipsHidden(t *testing.T) {
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.Add("build", NewMockCommand(nil))
+	internal := NewMockCommand(nil)
+	internal.Hide()
+	app.Add("bump", internal)
+
+	out := completeOutput(app, "b")
+	assertContains(t, out, "build\t")
+	if strings.Contains(out, "bump") {
+		t.Fatalf("expected hidden command to be left out of completion, got %q", out)
+	}
+}
+
 func Test_App_Commands(t *testing.T) {
 	app := newTestApp(Config{}, GlobalFlags{})
 	assertEmpty(t, app.Commands())
@@ -924,6 +1017,7 @@ func Test_GlobalFlags_ArgNames(t *testing.T) {
 		"HelpValues": argHelpValues,
 		"HelpFormat": argHelpFormat,
 		"Version":    argVersion,
+		"NoInput":    argNoInput,
 	}
 	typ := reflect.TypeOf(GlobalFlags{})
 	for field, argName := range want {
@@ -18,6 +18,10 @@ const tagShort = "short"
 // any run of leading dashes is trimmed before matching.
 const optionPrefix = "-"
 
+// argsTerminator ends option parsing: every argument after it is passed to the command verbatim,
+// in order, as Unknowns.Passthrough.
+const argsTerminator = "--"
+
 // matchField returns the field whose arg or short tag equals name, or nil when
 // no field claims that name. name is the bare flag (no dashes) or, for a
 // positional argument, its index rendered as a string ("0", "1", ...).
@@ -87,25 +91,40 @@ func matchNestedField(fields []structs.Field, name string) *structs.Field {
 // real argument tries field "0", the second tries "1", and so on. Flags (and the values
 // they consume) are not counted, so a positional still lands in the right slot no matter
 // how many flags come before it (e.g. "--cwd /tmp deploy" makes "deploy" the first one).
+//
+// Parsing stops at a "--" terminator; what follows it is for passthroughArgs, not any bucket here.
 func getCommandArgs(args []string, fields []structs.Field) ([]string, []string, map[string]any, map[string]any) {
+	parsedArgs, unknownArgs, parsedOptions, unknownOptions, _ := scanCommandArgs(args, fields)
+
+	return parsedArgs, unknownArgs, parsedOptions, unknownOptions
+}
+
+// scanCommandArgs is getCommandArgs, also returning the index in args of every positional it found,
+// known or unknown, in order: a token a flag consumed as its value is not one.
+func scanCommandArgs(args []string, fields []structs.Field) ([]string, []string, map[string]any, map[string]any, []int) {
 	if len(args) < 1 {
-		return []string{}, []string{}, map[string]any{}, map[string]any{}
+		return []string{}, []string{}, map[string]any{}, map[string]any{}, nil
 	}
 
 	parsedArgs := make([]string, 0)
 	unknownArgs := make([]string, 0)
 	parsedOptions := make(map[string]any)
 	unknownOptions := make(map[string]any)
+	positions := make([]int, 0)
 
 	ordinal := 0
 	for index := 0; index < len(args); index++ {
 		arg := args[index]
+		if arg == argsTerminator {
+			break
+		}
 
 		// positional: matched against a numeric arg tag by how many positionals came
 		// before it (flags don't count), else unknown.
 		if !strings.HasPrefix(arg, optionPrefix) {
 			foundField := matchField(fields, strconv.Itoa(ordinal))
 			ordinal++
+			positions = append(positions, index)
 			if foundField != nil {
 				parsedArgs = append(parsedArgs, arg)
 			} else {
@@ -120,8 +139,12 @@ func getCommandArgs(args []string, fields []structs.Field) ([]string, []string,
 		if strings.Contains(dePrefixedArg, "=") {
 			optName, optValue := splitKeyValue(dePrefixedArg)
 			foundField := matchField(fields, optName)
-			if foundField != nil {
+			if foundField != nil && FieldCounter(*foundField) {
+				setCount(parsedOptions, *foundField, optName, optValue)
+			} else if foundField != nil {
 				addMatchedOption(parsedOptions, optName, optValue, isSliceField(foundField))
+			} else if flag := negatedFlag(fields, optName); flag != "" {
+				parsedOptions[flag] = negatedValue(optValue)
 			} else {
 				addUnknownOption(unknownOptions, optName, optValue)
 			}
@@ -135,6 +158,11 @@ func getCommandArgs(args []string, fields []structs.Field) ([]string, []string,
 				parsedOptions[dePrefixedArg] = true
 				continue
 			}
+			// nor does a counter: each mention adds one.
+			if FieldCounter(*foundField) {
+				addCount(parsedOptions, *foundField, dePrefixedArg, 1)
+				continue
+			}
 
 			// otherwise the value is the next token, which is then consumed - but only when that token
 			// is not itself a flag. This stops "--steps --help" (or any flag followed by another flag)
@@ -148,6 +176,18 @@ func getCommandArgs(args []string, fields []structs.Field) ([]string, []string,
 			continue
 		}
 
+		// "-vvv" is a counter's short repeated, one per letter.
+		if counter, n := matchRepeatedShort(fields, dePrefixedArg); counter != nil {
+			addCount(parsedOptions, *counter, dePrefixedArg, n)
+			continue
+		}
+
+		// "--no-<flag>" turns a bool flag off (see negatedFlag).
+		if flag := negatedFlag(fields, dePrefixedArg); flag != "" {
+			parsedOptions[flag] = false
+			continue
+		}
+
 		// unknown flag: take the next token as its value when present and not itself a flag
 		// (consuming it), otherwise record it as a bare boolean true.
 		if len(args) > index+1 && !strings.HasPrefix(args[index+1], optionPrefix) {
@@ -158,19 +198,31 @@ func getCommandArgs(args []string, fields []structs.Field) ([]string, []string,
 		addUnknownOption(unknownOptions, dePrefixedArg, true)
 	}
 
-	return parsedArgs, unknownArgs, parsedOptions, unknownOptions
+	return parsedArgs, unknownArgs, parsedOptions, unknownOptions, positions
+}
+
+// passthroughArgs returns the arguments after the first "--" terminator in args, nil when there is none.
+func passthroughArgs(args []string) []string {
+	for i, arg := range args {
+		if arg == argsTerminator {
+			return args[i+1:]
+		}
+	}
+
+	return nil
 }
 
-// isSliceField reports whether a matched field is slice-typed, so a flag naming
-// it should accumulate its repeated occurrences rather than overwrite.
+// isSliceField reports whether a matched field is slice- or map-typed, so a flag
+// naming it should accumulate its repeated occurrences rather than overwrite.
 func isSliceField(field *structs.Field) bool {
-	return field != nil && field.Kind == reflect.Slice
+	return field != nil && (field.Kind == reflect.Slice || field.Kind == reflect.Map)
 }
 
-// addMatchedOption records a matched flag's value. A slice-typed field gathers
-// every occurrence into a structs.MultiValue, so `-r a -r b` keeps both values
-// and the downstream setter still splits each on the field's sep tag; a scalar
-// field keeps last-wins by overwriting.
+// addMatchedOption records a matched flag's value. A slice- or map-typed field
+// gathers every occurrence into a structs.MultiValue, so `-r a -r b` keeps both
+// values (and `--label env=prod --label team=core` both entries) and the
+// downstream setter still splits each on the field's sep tag; a scalar field
+// keeps last-wins by overwriting.
 func addMatchedOption(options map[string]any, name, value string, slice bool) {
 	if !slice {
 		options[name] = value
@@ -454,8 +454,50 @@ func Test_getCommandArgs_EdgeCases(t *testing.T) {
 	}
 }
 
+func Test_passthroughArgs(t *testing.T) {
+	assertEqual(t, []string{"ls", "-la", "--", "--color"}, passthroughArgs([]string{"exec", "--", "ls", "-la", "--", "--color"}))
+	assertEqual(t, []string{}, passthroughArgs([]string{"exec", "--"}))
+	assertNil(t, passthroughArgs([]string{"exec", "-la"}))
+}
+
+func Test_getCommandArgs_Negated(t *testing.T) {
+	type negatable struct {
+		Color   bool   `arg:"color" default:"true"`
+		NoCache bool   `arg:"no-cache"`
+		Cache   bool   `arg:"cache"`
+		Strict  bool   `arg:"strict" negatable:"false"`
+		Name    string `arg:"name"`
+	}
+	fields, err := structs.GetStructFields(&negatable{}, nil, structs.DefaultEncodingTags)
+	assertNoError(t, err)
+
+	tests := []struct {
+		name     string
+		args     []string
+		options  map[string]any
+		unknowns map[string]any
+	}{
+		{name: "bare", args: []string{"--no-color"}, options: map[string]any{"color": false}, unknowns: map[string]any{}},
+		{name: "explicit true", args: []string{"--no-color=true"}, options: map[string]any{"color": false}, unknowns: map[string]any{}},
+		{name: "explicit false", args: []string{"--no-color=false"}, options: map[string]any{"color": true}, unknowns: map[string]any{}},
+		{name: "last wins", args: []string{"--color", "--no-color"}, options: map[string]any{"color": false}, unknowns: map[string]any{}},
+		{name: "a field named no- wins", args: []string{"--no-cache"}, options: map[string]any{"no-cache": true}, unknowns: map[string]any{}},
+		{name: "opted out", args: []string{"--no-strict"}, options: map[string]any{}, unknowns: map[string]any{"no-strict": true}},
+		{name: "not a bool", args: []string{"--no-name"}, options: map[string]any{}, unknowns: map[string]any{"no-name": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, options, unknowns := getCommandArgs(tt.args, fields)
+			assertEqual(t, tt.options, options, "options")
+			assertEqual(t, tt.unknowns, unknowns, "unknown options")
+		})
+	}
+}
+
 // positionals are matched by their order among the real arguments, not their raw index,
 // so a global flag in front of a positional must not knock it out of its declared slot.
+
 func Test_getCommandArgs_Positional(t *testing.T) {
 	type onePositional struct {
 		Cwd    string `arg:"cwd"`
@@ -498,6 +540,15 @@ func Test_getCommandArgs_Positional(t *testing.T) {
 			expectedOptions: map[string]any{},
 			unknownOptions:  map[string]any{},
 		},
+		{
+			name:            "nothing after the terminator is parsed or fills a slot",
+			args:            []string{"--cwd", "/x", "--", "ls", "-la", "--cwd", "/y"},
+			structure:       &onePositional{},
+			expectedArgs:    []string{},
+			unknownArgs:     []string{},
+			expectedOptions: map[string]any{"cwd": "/x"},
+			unknownOptions:  map[string]any{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -514,3 +565,17 @@ func Test_getCommandArgs_Positional(t *testing.T) {
 		})
 	}
 }
+
+func Test_getCommandArgs_MapFlags(t *testing.T) {
+	type labeled struct {
+		Labels map[string]string `arg:"label" short:"l"`
+	}
+	fields, err := structs.GetStructFields(&labeled{}, nil, structs.DefaultEncodingTags)
+	assertNoError(t, err)
+
+	_, _, options, _ := getCommandArgs([]string{"--label", "a=1", "--label=b=2,c=3", "-l", "d=4"}, fields)
+	assertEqual(t, map[string]any{
+		"label": structs.MultiValue{"a=1", "b=2,c=3"},
+		"l":     structs.MultiValue{"d=4"},
+	}, options, "each occurrence is kept whole, for the setter to split")
+}
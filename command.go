@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -17,9 +18,28 @@ var ErrValidationFailed = errors.New("validation failed")
 type BaseCommand[T any] struct {
 	command  string
 	commands []Command[any]
+	ctx      context.Context
+	hookSets commandHooks
+	aliases  []string
+	hidden   bool
+	dep      Deprecation
+	// passthrough marks the command as taking arguments after "--" (see AcceptPassthrough).
+	passthrough bool
+	parent      Command[any]
+	streams     IO
+	// envPrefix is the app's Config.EnvPrefix, bound when the command is added.
+	envPrefix string
+	// baseline is what Inputs held before the command's first run, restored before each later one.
+	baseline *T
 	Inputs   *T
 }
 
+// contextBinder is satisfied by every command embedding BaseCommand, so the App can hand
+// the run context to a plain Run without widening the Command interface.
+type contextBinder interface {
+	bindContext(ctx context.Context)
+}
+
 // NewBaseCommand returns a BaseCommand with an initialized subcommand slice.
 func NewBaseCommand[T any]() BaseCommand[T] {
 	return BaseCommand[T]{
@@ -40,14 +60,80 @@ func (c *BaseCommand[T]) Name(name string) string {
 	return name
 }
 
+// Context returns the context the command is running under: the App's signal-derived context
+// during App.Run (cancelled on the first Ctrl-C), the caller's context during App.RunContext,
+// and context.Background when the command is invoked directly (e.g. from a test).
+// Long-running commands select on Context().Done() to shut down cleanly.
+func (c *BaseCommand[T]) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+
+	return c.ctx
+}
+
+// IO returns the streams and environment the command runs with: those set with App.IO during
+// App.Run, and the process's own when the command is invoked directly. Write output to IO().Out
+// rather than os.Stdout so a test can capture it without touching process globals.
+func (c *BaseCommand[T]) IO() IO {
+	return c.streams.withDefaults()
+}
+
+// bindIO sets the streams IO returns; the App calls it right before Run.
+func (c *BaseCommand[T]) bindIO(streams IO) {
+	c.streams = streams
+}
+
+// bindContext sets the context Context returns; the App calls it right before Run.
+func (c *BaseCommand[T]) bindContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// PreRun registers hooks run before this command's Run, once its inputs are resolved.
+func (c *BaseCommand[T]) PreRun(hooks ...Hook) {
+	c.hookSets.local.pre = append(c.hookSets.local.pre, hooks...)
+}
+
+// PostRun registers hooks run after this command's Run succeeds.
+func (c *BaseCommand[T]) PostRun(hooks ...Hook) {
+	c.hookSets.local.post = append(c.hookSets.local.post, hooks...)
+}
+
+// OnError registers hooks that receive the error this command's Run returned.
+func (c *BaseCommand[T]) OnError(hooks ...ErrorHook) {
+	c.hookSets.local.onError = append(c.hookSets.local.onError, hooks...)
+}
+
+// PersistentPreRun registers hooks run before this command's Run and before the Run of every
+// subcommand beneath it, however deep. An ancestor's persistent hooks run before a descendant's.
+func (c *BaseCommand[T]) PersistentPreRun(hooks ...Hook) {
+	c.hookSets.persistent.pre = append(c.hookSets.persistent.pre, hooks...)
+}
+
+// PersistentPostRun registers hooks run after this command's Run, and every subcommand's, succeeds.
+func (c *BaseCommand[T]) PersistentPostRun(hooks ...Hook) {
+	c.hookSets.persistent.post = append(c.hookSets.persistent.post, hooks...)
+}
+
+// PersistentOnError registers hooks that receive the Run error of this command and of every subcommand beneath it.
+func (c *BaseCommand[T]) PersistentOnError(hooks ...ErrorHook) {
+	c.hookSets.persistent.onError = append(c.hookSets.persistent.onError, hooks...)
+}
+
+// hooks returns the command's registered hooks for the App to collect at dispatch.
+func (c *BaseCommand[T]) hooks() *commandHooks {
+	return &c.hookSets
+}
+
 // Add registers cmd as a subcommand under the given name.
 func (c *BaseCommand[T]) Add(name string, cmd Command[any]) {
 	cmd.Name(name)
+	bindEnvPrefix(cmd, c.envPrefix)
 	c.commands = append(c.commands, cmd)
 }
 
 // Validate checks the parsed options against the struct rules on the command's
-// config type, returning ErrValidationFailed when any rule fails.
+// config type, returning a *ValidationError (matching ErrValidationFailed) when any rule fails.
 func (c *BaseCommand[T]) Validate(options map[string]any) error {
 	manager := structs.New(c.Inputs, structs.WithTags(defaultTags...))
 	validationErrs, err := manager.Validate(options)
@@ -56,7 +142,7 @@ func (c *BaseCommand[T]) Validate(options map[string]any) error {
 	}
 
 	if len(validationErrs) > 0 {
-		return fmt.Errorf("validation failed: %v: %w", validationErrs, ErrValidationFailed)
+		return newValidationError(c.Inputs, c.envPrefix, validationErrs, options)
 	}
 
 	return nil
@@ -79,6 +165,24 @@ func (c *BaseCommand[T]) Options() any {
 	return c.Inputs
 }
 
+// inputsResetter is satisfied by every command embedding BaseCommand, so the App can clear
+// a previous run's inputs before the next run of the same command.
+type inputsResetter interface {
+	resetInputs()
+}
+
+// resetInputs restores Inputs to what it held before the first run: the zero T, or the values of
+// the struct an app assigned to it. The pointer itself is kept, so an assigned Inputs stays shared.
+func (c *BaseCommand[T]) resetInputs() {
+	c.Options()
+	if c.baseline == nil {
+		baseline := *c.Inputs
+		c.baseline = &baseline
+		return
+	}
+	*c.Inputs = *c.baseline
+}
+
 // Commands returns the registered subcommands.
 func (c *BaseCommand[T]) Commands() []Command[any] {
 	return c.commands
@@ -95,3 +199,90 @@ func (c *BaseCommand[T]) Args() map[int][]string { return nil }
 
 // Flags returns no flag descriptions by default. Override to provide them.
 func (c *BaseCommand[T]) Flags() map[string][]string { return nil }
+
+// Alias adds alternative names the command answers to, e.g. cmd.Alias("rm") on a "remove" command,
+// so a rename (ls -> list) can keep the old spelling working.
+func (c *BaseCommand[T]) Alias(aliases ...string) {
+	c.aliases = append(c.aliases, aliases...)
+}
+
+// Aliases returns the names added with Alias, or nil.
+func (c *BaseCommand[T]) Aliases() []string { return c.aliases }
+
+// aliasProvider is satisfied by every command embedding BaseCommand, so a command can answer to other
+// names without widening the Command interface.
+type aliasProvider interface {
+	Aliases() []string
+}
+
+// CommandAliases returns the alternative names cmd answers to (see BaseCommand.Alias), honored by dispatch,
+// completion, and help filtering, and listed in help. Nil when cmd has none.
+func CommandAliases(cmd Command[any]) []string {
+	if p, ok := cmd.(aliasProvider); ok {
+		return p.Aliases()
+	}
+
+	return nil
+}
+
+// Hide keeps the command runnable but drops it from every help listing and from completion,
+// for internal or experimental commands. Use Deprecate for commands on their way out.
+func (c *BaseCommand[T]) Hide() {
+	c.hidden = true
+}
+
+// PassthroughAccepter is implemented by commands that can report whether they take arguments after
+// a "--" terminator. BaseCommand implements it; help reads it to show "[-- args...]".
+type PassthroughAccepter interface {
+	AcceptsPassthrough() bool
+}
+
+// AcceptPassthrough marks the command as taking arguments after a "--" terminator (Unknowns.Passthrough),
+// so help shows "[-- args...]" for it. The arguments reach every command either way.
+func (c *BaseCommand[T]) AcceptPassthrough() { c.passthrough = true }
+
+// AcceptsPassthrough reports whether AcceptPassthrough was called.
+func (c *BaseCommand[T]) AcceptsPassthrough() bool { return c.passthrough }
+
+// Hidden reports whether Hide was called.
+func (c *BaseCommand[T]) Hidden() bool { return c.hidden }
+
+// hiddenProvider is satisfied by every command embedding BaseCommand, so a command can be hidden
+// without widening the Command interface.
+type hiddenProvider interface {
+	Hidden() bool
+}
+
+// CommandHidden reports whether cmd is left out of every help listing and of completion (see BaseCommand.Hide).
+// A hidden command still runs when invoked by name.
+func CommandHidden(cmd Command[any]) bool {
+	p, ok := cmd.(hiddenProvider)
+
+	return ok && p.Hidden()
+}
+
+// Deprecate marks the command as deprecated with message and, optionally, the command path to use
+// instead (e.g. "db migrate"). The command keeps running, after a one-time warning.
+func (c *BaseCommand[T]) Deprecate(message, replacement string) {
+	c.dep = Deprecation{Message: message, Replacement: replacement}
+}
+
+// Deprecated returns the deprecation set with Deprecate, or the zero Deprecation.
+// Override it to compute the deprecation instead.
+func (c *BaseCommand[T]) Deprecated() Deprecation { return c.dep }
+
+// Parent returns the command this one was dispatched under (e.g. "db" for "db migrate"),
+// or nil for a top-level command or one invoked directly. It is set when the App runs the command.
+func (c *BaseCommand[T]) Parent() Command[any] { return c.parent }
+
+// EnvPrefix returns the Config.EnvPrefix of the app the command was added to, or "" before it is added.
+func (c *BaseCommand[T]) EnvPrefix() string { return c.envPrefix }
+
+func (c *BaseCommand[T]) bindEnvPrefix(prefix string) {
+	c.envPrefix = prefix
+}
+
+// bindParent sets the command Parent returns; the App calls it at dispatch.
+func (c *BaseCommand[T]) bindParent(parent Command[any]) {
+	c.parent = parent
+}
@@ -131,3 +131,42 @@ func Test_BaseCommand_Validate_RequiredFails(t *testing.T) {
 	assertError(t, err)
 	assertErrorIs(t, err, ErrValidationFailed)
 }
+
+// bareCommand implements Command without embedding BaseCommand.
+type bareCommand struct {
+	name string
+	ran  bool
+}
+
+var _ Command[any] = (*bareCommand)(nil)
+
+func (c *bareCommand) Name(name string) string {
+	if name != "" {
+		c.name = name
+	}
+	return c.name
+}
+func (c *bareCommand) Add(string, Command[any])        {}
+func (c *bareCommand) Options() any                    { return &TestConfig{} }
+func (c *bareCommand) Commands() []Command[any]        { return nil }
+func (c *bareCommand) Run(GlobalFlags, Unknowns) error { c.ran = true; return nil }
+func (c *bareCommand) Validate(map[string]any) error   { return nil }
+func (c *bareCommand) Help() string                    { return "bare" }
+func (c *bareCommand) Description() string             { return "" }
+func (c *bareCommand) Examples() [][]string            { return nil }
+func (c *bareCommand) Args() map[int][]string          { return nil }
+func (c *bareCommand) Flags() map[string][]string      { return nil }
+func (c *bareCommand) Deprecated() Deprecation         { return Deprecation{} }
+
+func Test_Command_WithoutBaseCommand(t *testing.T) {
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.Add("help", NewMockCommand(func() error { return nil }))
+	cmd := &bareCommand{}
+	app.Add("bare", cmd)
+
+	assertNoError(t, app.Run([]string{"bare"}))
+	assertEqual(t, true, cmd.ran)
+	assertLen(t, CommandAliases(cmd), 0, "no aliases without BaseCommand")
+	assertEqual(t, false, CommandHidden(cmd), "listed without BaseCommand")
+	assertEqual(t, false, CommandDeprecation(cmd).IsDeprecated(), "not deprecated without BaseCommand")
+}
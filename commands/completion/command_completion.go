@@ -11,7 +11,7 @@ import (
 // Config holds the inputs for the completion command.
 type Config struct {
 	// Shell is the shell type to generate completions for.
-	Shell string `arg:"0" help:"Shell type: bash, zsh, fish" rules:"required"`
+	Shell string `arg:"0" help:"Shell type: bash, zsh, fish, pwsh, nu" rules:"required"`
 }
 
 // Command generates shell completion scripts.
@@ -23,9 +23,14 @@ type Command struct {
 
 var _ cli.Command[Config] = (*Command)(nil)
 
-// NewCompletionCommand creates a completion command for the given app name.
+// NewCompletionCommand creates a completion command for the given app name, with its install and
+// uninstall subcommands.
 func NewCompletionCommand(appName string) *Command {
-	return &Command{appName: appName}
+	c := &Command{appName: appName}
+	c.Add("install", newInstallCommand(c, false))
+	c.Add("uninstall", newInstallCommand(c, true))
+
+	return c
 }
 
 // Run writes the completion script for the requested shell to stdout.
@@ -35,6 +40,17 @@ func (c *Command) Run(_ cli.GlobalFlags, _ cli.Unknowns) error {
 		shell = c.Inputs.Shell
 	}
 
+	output, err := c.script(shell)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(c.IO().Out, output)
+	return nil
+}
+
+// script returns the completion script for shell with the app name filled in.
+func (c *Command) script(shell string) (string, error) {
 	var filename string
 	switch strings.ToLower(shell) {
 	case "bash":
@@ -43,18 +59,20 @@ func (c *Command) Run(_ cli.GlobalFlags, _ cli.Unknowns) error {
 		filename = "scripts/zsh.sh"
 	case "fish":
 		filename = "scripts/fish.sh"
+	case "pwsh", "powershell":
+		filename = "scripts/pwsh.ps1"
+	case "nu", "nushell":
+		filename = "scripts/nu.nu"
 	default:
-		return fmt.Errorf("unsupported shell %q, supported: bash, zsh, fish", shell)
+		return "", fmt.Errorf("unsupported shell %q, supported: bash, zsh, fish, pwsh, nu", shell)
 	}
 
 	data, err := scripts.ReadFile(filename)
 	if err != nil {
-		return fmt.Errorf("failed to read completion script for %s: %w", shell, err)
+		return "", fmt.Errorf("failed to read completion script for %s: %w", shell, err)
 	}
 
-	output := strings.ReplaceAll(string(data), "{{.AppName}}", c.appName)
-	fmt.Print(output)
-	return nil
+	return strings.ReplaceAll(string(data), "{{.AppName}}", c.appName), nil
 }
 
 // Help returns the one-line help summary for the command.
@@ -71,8 +89,13 @@ func (c *Command) Description() string {
 		"  bash:  " + c.appName + " completion bash > /etc/bash_completion.d/" + c.appName,
 		"  zsh:   " + c.appName + ` completion zsh > "${fpath[1]}/_` + c.appName + `"`,
 		"  fish:  " + c.appName + " completion fish > ~/.config/fish/completions/" + c.appName + ".fish",
+		"  pwsh:  " + c.appName + " completion pwsh >> $PROFILE",
+		"  nu:    " + c.appName + " completion nu | save -f ~/.config/nushell/" + c.appName + "-completions.nu",
+		"         then add `source ~/.config/nushell/" + c.appName + "-completions.nu` to your config.nu",
 		"",
 		"Then restart your shell or source the file to enable completions.",
+		"",
+		"Or let " + c.appName + " do it for bash, zsh, or fish: " + c.appName + " completion install [shell]",
 	}, "\n")
 }
 
@@ -82,6 +105,9 @@ func (c *Command) Examples() [][]string {
 		{c.appName + ` completion bash > /etc/bash_completion.d/` + c.appName},
 		{c.appName + ` completion zsh > "${fpath[1]}/_` + c.appName + `"`},
 		{c.appName + ` completion fish > ~/.config/fish/completions/` + c.appName + `.fish`},
+		{c.appName + ` completion pwsh >> $PROFILE`},
+		{c.appName + ` completion nu | save -f ~/.config/nushell/` + c.appName + `-completions.nu`},
+		{c.appName + ` completion install`},
 	}
 }
 
@@ -90,7 +116,8 @@ func (c *Command) Args() map[int][]string {
 	return map[int][]string{
 		0: {
 			"The shell to generate a completion script for.",
-			"One of: bash, zsh, fish.",
+			"One of: bash, zsh, fish, pwsh (powershell), nu (nushell).",
+			"pwsh: append to $PROFILE; nu: save to a file and `source` it from config.nu.",
 		},
 	}
 }
@@ -2,14 +2,18 @@ package completion
 
 import (
 	"bytes"
+	"flag"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/toaweme/cli"
 )
 
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
 func captureStdout(t *testing.T, fn func()) string {
 	t.Helper()
 	orig := os.Stdout
@@ -40,6 +44,10 @@ func Test_CompletionCommand_Run(t *testing.T) {
 		{name: "bash", shell: "bash"},
 		{name: "zsh", shell: "zsh"},
 		{name: "fish", shell: "fish"},
+		{name: "pwsh", shell: "pwsh"},
+		{name: "powershell alias", shell: "powershell"},
+		{name: "nu", shell: "nu"},
+		{name: "nushell alias", shell: "nushell"},
 		{name: "uppercase is normalized", shell: "BASH"},
 	}
 
@@ -69,7 +77,7 @@ func Test_CompletionCommand_Run(t *testing.T) {
 
 func Test_CompletionCommand_Run_UnsupportedShell(t *testing.T) {
 	cmd := NewCompletionCommand("myapp")
-	cmd.Inputs = &Config{Shell: "powershell"}
+	cmd.Inputs = &Config{Shell: "tcsh"}
 
 	err := cmd.Run(cli.GlobalFlags{}, cli.Unknowns{})
 	if err == nil {
@@ -93,8 +101,8 @@ func Test_CompletionCommand_Examples(t *testing.T) {
 	cmd := NewCompletionCommand("myapp")
 	examples := cmd.Examples()
 
-	if len(examples) != 3 {
-		t.Fatalf("expected 3 examples, got %d", len(examples))
+	if len(examples) != 6 {
+		t.Fatalf("expected 6 examples, got %d", len(examples))
 	}
 	for _, ex := range examples {
 		if len(ex) == 0 {
@@ -118,7 +126,7 @@ func Test_CompletionCommand_Args(t *testing.T) {
 		t.Fatalf("expected a multi-line description, got %d line(s)", len(lines))
 	}
 	joined := strings.Join(lines, "\n")
-	for _, want := range []string{"bash", "zsh", "fish"} {
+	for _, want := range []string{"bash", "zsh", "fish", "pwsh", "nu"} {
 		if !strings.Contains(joined, want) {
 			t.Fatalf("expected arg description to mention %q, got %q", want, joined)
 		}
@@ -139,9 +147,39 @@ func Test_CompletionCommand_Description(t *testing.T) {
 	if !strings.Contains(desc, "\n") {
 		t.Fatalf("expected a multi-line description, got %q", desc)
 	}
-	for _, want := range []string{"Install", "myapp completion bash", "myapp completion zsh", "myapp completion fish"} {
+	for _, want := range []string{"Install", "myapp completion bash", "myapp completion zsh", "myapp completion fish", "myapp completion pwsh >> $PROFILE", "myapp completion nu | save"} {
 		if !strings.Contains(desc, want) {
 			t.Fatalf("description missing %q in:\n%s", want, desc)
 		}
 	}
 }
+
+// Test_CompletionCommand_Golden pins the generated scripts; run with -update after changing a template.
+func Test_CompletionCommand_Golden(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "pwsh", "nu"} {
+		t.Run(shell, func(t *testing.T) {
+			cmd := NewCompletionCommand("myapp")
+			cmd.Inputs = &Config{Shell: shell}
+
+			out := captureStdout(t, func() {
+				if err := cmd.Run(cli.GlobalFlags{}, cli.Unknowns{}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+
+			golden := filepath.Join("testdata", shell+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(out), 0o644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if out != string(want) {
+				t.Fatalf("script for %s differs from %s:\n%s", shell, golden, out)
+			}
+		})
+	}
+}
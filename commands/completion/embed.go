@@ -2,5 +2,5 @@ package completion
 
 import "embed"
 
-//go:embed scripts/*.sh
+//go:embed scripts/*
 var scripts embed.FS
@@ -0,0 +1,353 @@
+package completion
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/toaweme/cli"
+)
+
+// InstallConfig holds the inputs for the install and uninstall subcommands.
+type InstallConfig struct {
+	// Shell is the shell to install for; empty means the one $SHELL names.
+	Shell string `arg:"0" help:"Shell type: bash, zsh, fish (default: from $SHELL)"`
+	// DryRun prints what would change without touching any file.
+	DryRun bool `arg:"dry-run" help:"Print what would change without writing anything"`
+}
+
+// InstallCommand writes the completion script to the shell's per-user completion directory, or
+// removes it from there when built as the uninstall subcommand.
+type InstallCommand struct {
+	cli.BaseCommand[InstallConfig]
+
+	completion *Command
+	uninstall  bool
+}
+
+var _ cli.Command[InstallConfig] = (*InstallCommand)(nil)
+
+// newInstallCommand creates the install subcommand of completion, or the uninstall one.
+func newInstallCommand(completion *Command, uninstall bool) *InstallCommand {
+	return &InstallCommand{
+		BaseCommand: cli.NewBaseCommand[InstallConfig](),
+		completion:  completion,
+		uninstall:   uninstall,
+	}
+}
+
+// Run installs or uninstalls the script for the requested shell, reporting what it did (or, with
+// --dry-run, would do) on stdout. Both are idempotent: a file already in the wanted state is left alone.
+// For zsh it also keeps the script's directory on the fpath in .zshrc, which zsh does not load by default.
+func (c *InstallCommand) Run(_ cli.GlobalFlags, _ cli.Unknowns) error {
+	inputs := InstallConfig{}
+	if c.Inputs != nil {
+		inputs = *c.Inputs
+	}
+
+	shell, err := c.shell(inputs.Shell)
+	if err != nil {
+		return err
+	}
+	path, err := c.scriptPath(shell)
+	if err != nil {
+		return err
+	}
+
+	if c.uninstall {
+		if err := c.remove(path, inputs.DryRun); err != nil {
+			return err
+		}
+		if shell == "zsh" {
+			return c.dropFpath(path, inputs.DryRun)
+		}
+		return nil
+	}
+
+	script, err := c.completion.script(shell)
+	if err != nil {
+		return err
+	}
+	if err := c.write(path, script, inputs.DryRun); err != nil {
+		return err
+	}
+	if shell == "zsh" {
+		return c.addFpath(inputs.DryRun)
+	}
+
+	return nil
+}
+
+// write puts script at path unless it is already there.
+func (c *InstallCommand) write(path, script string, dryRun bool) error {
+	current, err := os.ReadFile(path)
+	switch {
+	case err == nil && bytes.Equal(current, []byte(script)):
+		fmt.Fprintf(c.IO().Out, "up to date: %s\n", path)
+		return nil
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	verb, done := "install", "installed"
+	if err == nil {
+		verb, done = "update", "updated"
+	}
+	if dryRun {
+		fmt.Fprintf(c.IO().Out, "would %s: %s\n", verb, path)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Fprintf(c.IO().Out, "%s: %s\n", done, path)
+
+	return nil
+}
+
+// zshFpathLine is the .zshrc line putting the directory zsh scripts are installed to on the fpath.
+const zshFpathLine = "fpath=(${ZDOTDIR:-$HOME}/.zfunc $fpath)"
+
+// addFpath adds zshFpathLine to .zshrc unless it is there already. It goes before the first line that
+// loads completions (compinit, directly or through oh-my-zsh), which only reads the fpath it is given.
+func (c *InstallCommand) addFpath(dryRun bool) error {
+	rc, lines, err := c.zshrc()
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(lines, isFpathLine) {
+		fmt.Fprintf(c.IO().Out, "up to date: %s\n", rc)
+		return nil
+	}
+	if dryRun {
+		fmt.Fprintf(c.IO().Out, "would add the fpath entry to: %s\n", rc)
+		return nil
+	}
+
+	at := slices.IndexFunc(lines, loadsCompletions)
+	if at < 0 {
+		at = len(lines)
+	}
+	if err := writeLines(rc, slices.Insert(lines, at, zshFpathLine)); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.IO().Out, "added the fpath entry to: %s\n", rc)
+
+	return nil
+}
+
+// dropFpath removes zshFpathLine from .zshrc once the script at path was the last one in its directory.
+func (c *InstallCommand) dropFpath(path string, dryRun bool) error {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", filepath.Dir(path), err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != filepath.Base(path) {
+			return nil
+		}
+	}
+
+	rc, lines, err := c.zshrc()
+	if err != nil {
+		return err
+	}
+	kept := slices.DeleteFunc(slices.Clone(lines), isFpathLine)
+	if len(kept) == len(lines) {
+		return nil
+	}
+	if dryRun {
+		fmt.Fprintf(c.IO().Out, "would remove the fpath entry from: %s\n", rc)
+		return nil
+	}
+	if err := writeLines(rc, kept); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.IO().Out, "removed the fpath entry from: %s\n", rc)
+
+	return nil
+}
+
+// zshrc returns the path of ${ZDOTDIR:-~}/.zshrc and its lines, none when it does not exist yet.
+func (c *InstallCommand) zshrc() (string, []string, error) {
+	dir, _ := c.IO().Env.LookupEnv("ZDOTDIR")
+	if dir == "" {
+		dir, _ = c.IO().Env.LookupEnv("HOME")
+	}
+	rc := filepath.Join(dir, ".zshrc")
+
+	data, err := os.ReadFile(rc)
+	if errors.Is(err, fs.ErrNotExist) {
+		return rc, nil, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read %s: %w", rc, err)
+	}
+	if len(data) == 0 {
+		return rc, nil, nil
+	}
+
+	return rc, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// writeLines writes lines to path, each ending in a newline.
+func writeLines(path string, lines []string) error {
+	data := ""
+	if len(lines) > 0 {
+		data = strings.Join(lines, "\n") + "\n"
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// isFpathLine reports whether line is zshFpathLine.
+func isFpathLine(line string) bool {
+	return strings.TrimSpace(line) == zshFpathLine
+}
+
+// loadsCompletions reports whether line runs compinit, directly or by sourcing oh-my-zsh.
+func loadsCompletions(line string) bool {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "#") {
+		return false
+	}
+
+	return strings.Contains(line, "compinit") || strings.Contains(line, "oh-my-zsh.sh")
+}
+
+// remove deletes the script at path when it is there.
+func (c *InstallCommand) remove(path string, dryRun bool) error {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(c.IO().Out, "not installed: %s\n", path)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	if dryRun {
+		fmt.Fprintf(c.IO().Out, "would remove: %s\n", path)
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	fmt.Fprintf(c.IO().Out, "removed: %s\n", path)
+
+	return nil
+}
+
+// shell is the shell to install for: the one given, else the one $SHELL names.
+func (c *InstallCommand) shell(shell string) (string, error) {
+	if shell == "" {
+		login, _ := c.IO().Env.LookupEnv("SHELL")
+		shell = filepath.Base(login)
+		if login == "" {
+			return "", errors.New("failed to detect the shell: $SHELL is not set, pass one of bash, zsh, fish")
+		}
+	}
+
+	shell = strings.ToLower(shell)
+	switch shell {
+	case "bash", "zsh", "fish":
+		return shell, nil
+	}
+
+	return "", fmt.Errorf("unsupported shell %q for install, supported: bash, zsh, fish", shell)
+}
+
+// scriptPath is where shell loads the app's completion script from for the current user:
+//
+//	bash: ${BASH_COMPLETION_USER_DIR:-${XDG_DATA_HOME:-~/.local/share}/bash-completion}/completions/<app>
+//	zsh:  ${ZDOTDIR:-~}/.zfunc/_<app>
+//	fish: ${XDG_CONFIG_HOME:-~/.config}/fish/completions/<app>.fish
+func (c *InstallCommand) scriptPath(shell string) (string, error) {
+	env := c.IO().Env
+	lookup := func(key string) string {
+		value, _ := env.LookupEnv(key)
+		return value
+	}
+	home := lookup("HOME")
+	if home == "" {
+		return "", errors.New("failed to find the home directory: $HOME is not set")
+	}
+	under := func(key string, fallback ...string) string {
+		if dir := lookup(key); dir != "" {
+			return dir
+		}
+		return filepath.Join(append([]string{home}, fallback...)...)
+	}
+
+	app := c.completion.appName
+	switch shell {
+	case "bash":
+		dir := lookup("BASH_COMPLETION_USER_DIR")
+		if dir == "" {
+			dir = filepath.Join(under("XDG_DATA_HOME", ".local", "share"), "bash-completion")
+		}
+		return filepath.Join(dir, "completions", app), nil
+	case "zsh":
+		return filepath.Join(under("ZDOTDIR"), ".zfunc", "_"+app), nil
+	default:
+		return filepath.Join(under("XDG_CONFIG_HOME", ".config"), "fish", "completions", app+".fish"), nil
+	}
+}
+
+// Help returns the one-line help summary for the command.
+func (c *InstallCommand) Help() string {
+	if c.uninstall {
+		return "Remove the installed shell completion script"
+	}
+	return "Install the shell completion script for the current user"
+}
+
+// Description returns the long-form description shown in help output.
+func (c *InstallCommand) Description() string {
+	app := c.completion.appName
+	lines := []string{
+		"Write the completion script for the current user, detecting the shell from $SHELL",
+		"unless one is given. Re-running is safe: an up-to-date script is left alone.",
+	}
+	if c.uninstall {
+		lines = []string{
+			"Remove the completion script installed for the current user, detecting the shell",
+			"from $SHELL unless one is given. Re-running is safe: a missing script is left alone.",
+		}
+	}
+
+	lines = append(lines,
+		"",
+		"Locations:",
+		"  bash:  ~/.local/share/bash-completion/completions/"+app+" ($BASH_COMPLETION_USER_DIR, $XDG_DATA_HOME)",
+		"  zsh:   ~/.zfunc/_"+app+" ($ZDOTDIR), put on the fpath by a line in ~/.zshrc ahead of compinit;",
+		"         uninstall takes the line out once no other script is left in ~/.zfunc",
+		"  fish:  ~/.config/fish/completions/"+app+".fish ($XDG_CONFIG_HOME)",
+	)
+
+	return strings.Join(lines, "\n")
+}
+
+// Examples returns example invocations shown in help output.
+func (c *InstallCommand) Examples() [][]string {
+	name := "install"
+	if c.uninstall {
+		name = "uninstall"
+	}
+	app := c.completion.appName
+
+	return [][]string{
+		{app + " completion " + name},
+		{app + " completion " + name + " zsh --dry-run"},
+	}
+}
@@ -0,0 +1,271 @@
+package completion
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/toaweme/cli"
+)
+
+// runInstall runs `myapp completion <args>` with HOME at home and the given env on top.
+func runInstall(t *testing.T, home string, env map[string]string, args ...string) (string, error) {
+	t.Helper()
+	vars := map[string]string{"HOME": home}
+	for k, v := range env {
+		vars[k] = v
+	}
+
+	var out bytes.Buffer
+	app := cli.NewApp(cli.Config{Name: "myapp"}, cli.GlobalFlags{})
+	app.IO(cli.IO{Out: &out, Err: &out, Env: cli.MapEnv(vars)})
+	app.Add("completion", NewCompletionCommand("myapp"))
+	err := app.Run(append([]string{"completion"}, args...))
+
+	return out.String(), err
+}
+
+func Test_InstallCommand_Paths(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{name: "bash from $SHELL", env: map[string]string{"SHELL": "/bin/bash"}, want: ".local/share/bash-completion/completions/myapp"},
+		{name: "zsh from $SHELL", env: map[string]string{"SHELL": "/usr/bin/zsh"}, want: ".zfunc/_myapp"},
+		{name: "fish from $SHELL", env: map[string]string{"SHELL": "/usr/local/bin/fish"}, want: ".config/fish/completions/myapp.fish"},
+		{name: "given shell wins", env: map[string]string{"SHELL": "/bin/bash"}, args: []string{"fish"}, want: ".config/fish/completions/myapp.fish"},
+		{name: "XDG_DATA_HOME", env: map[string]string{"SHELL": "/bin/bash", "XDG_DATA_HOME": "data"}, want: "data/bash-completion/completions/myapp"},
+		{name: "BASH_COMPLETION_USER_DIR", env: map[string]string{"BASH_COMPLETION_USER_DIR": "bc"}, args: []string{"bash"}, want: "bc/completions/myapp"},
+		{name: "ZDOTDIR", env: map[string]string{"ZDOTDIR": "zdot"}, args: []string{"zsh"}, want: "zdot/.zfunc/_myapp"},
+		{name: "XDG_CONFIG_HOME", env: map[string]string{"XDG_CONFIG_HOME": "conf"}, args: []string{"fish"}, want: "conf/fish/completions/myapp.fish"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			// relative env dirs resolve under home, like the absolute ones a user would set
+			env := make(map[string]string, len(tt.env))
+			for k, v := range tt.env {
+				if k != "SHELL" {
+					v = filepath.Join(home, v)
+				}
+				env[k] = v
+			}
+
+			out, err := runInstall(t, home, env, append([]string{"install"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			path := filepath.Join(home, tt.want)
+			if !strings.Contains(out, "installed: "+path) {
+				t.Fatalf("expected %s to be reported, got:\n%s", path, out)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("expected the script at %s: %v", path, err)
+			}
+			if !strings.Contains(string(data), "__complete") {
+				t.Fatalf("expected the completion script, got:\n%s", data)
+			}
+		})
+	}
+}
+
+func Test_InstallCommand_Idempotent(t *testing.T) {
+	home := t.TempDir()
+	env := map[string]string{"SHELL": "/bin/fish"}
+	path := filepath.Join(home, ".config", "fish", "completions", "myapp.fish")
+
+	steps := []struct {
+		args []string
+		want string
+		file bool
+	}{
+		{args: []string{"install", "--dry-run"}, want: "would install: " + path, file: false},
+		{args: []string{"install"}, want: "installed: " + path, file: true},
+		{args: []string{"install"}, want: "up to date: " + path, file: true},
+		{args: []string{"uninstall", "--dry-run"}, want: "would remove: " + path, file: true},
+		{args: []string{"uninstall"}, want: "removed: " + path, file: false},
+		{args: []string{"uninstall"}, want: "not installed: " + path, file: false},
+	}
+	for _, step := range steps {
+		out, err := runInstall(t, home, env, step.args...)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", step.args, err)
+		}
+		if !strings.Contains(out, step.want) {
+			t.Fatalf("%v: expected %q, got:\n%s", step.args, step.want, out)
+		}
+		if _, err := os.Stat(path); (err == nil) != step.file {
+			t.Fatalf("%v: expected the file to exist: %v, stat: %v", step.args, step.file, err)
+		}
+	}
+}
+
+func Test_InstallCommand_UpdatesStaleScript(t *testing.T) {
+	home := t.TempDir()
+	path := filepath.Join(home, ".zfunc", "_myapp")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runInstall(t, home, nil, "install", "zsh", "--dry-run")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "would update: "+path) {
+		t.Fatalf("expected a dry-run update, got:\n%s", out)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Fatalf("expected --dry-run to leave the file alone, got %q", data)
+	}
+
+	out, err = runInstall(t, home, nil, "install", "zsh")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "updated: "+path) {
+		t.Fatalf("expected an update, got:\n%s", out)
+	}
+
+	out, err = runInstall(t, home, nil, "install", "zsh")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "up to date: "+path) {
+		t.Fatalf("expected an up-to-date script, got:\n%s", out)
+	}
+}
+
+func Test_InstallCommand_ZshFpath(t *testing.T) {
+	home := t.TempDir()
+	rc := filepath.Join(home, ".zshrc")
+	if err := os.WriteFile(rc, []byte("export ZSH=~/.oh-my-zsh\nsource $ZSH/oh-my-zsh.sh\nalias k=kubectl\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(home, ".zfunc", "_other")
+	readRC := func() string {
+		data, err := os.ReadFile(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	steps := []struct {
+		name    string
+		args    []string
+		setup   func()
+		wantOut string
+		wantRC  string
+	}{
+		{
+			name:    "dry run leaves .zshrc alone",
+			args:    []string{"install", "zsh", "--dry-run"},
+			wantOut: "would add the fpath entry to: " + rc,
+			wantRC:  "export ZSH=~/.oh-my-zsh\nsource $ZSH/oh-my-zsh.sh\nalias k=kubectl\n",
+		},
+		{
+			name:    "added ahead of compinit",
+			args:    []string{"install", "zsh"},
+			wantOut: "added the fpath entry to: " + rc,
+			wantRC:  "export ZSH=~/.oh-my-zsh\n" + zshFpathLine + "\nsource $ZSH/oh-my-zsh.sh\nalias k=kubectl\n",
+		},
+		{
+			name:    "added once",
+			args:    []string{"install", "zsh"},
+			wantOut: "up to date: " + rc,
+			wantRC:  "export ZSH=~/.oh-my-zsh\n" + zshFpathLine + "\nsource $ZSH/oh-my-zsh.sh\nalias k=kubectl\n",
+		},
+		{
+			name: "kept while another script needs it",
+			args: []string{"uninstall", "zsh"},
+			setup: func() {
+				if err := os.WriteFile(other, []byte("#compdef other\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantOut: "removed: " + filepath.Join(home, ".zfunc", "_myapp"),
+			wantRC:  "export ZSH=~/.oh-my-zsh\n" + zshFpathLine + "\nsource $ZSH/oh-my-zsh.sh\nalias k=kubectl\n",
+		},
+		{
+			name: "removed with the last script",
+			args: []string{"uninstall", "zsh"},
+			setup: func() {
+				if err := os.Remove(other); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantOut: "removed the fpath entry from: " + rc,
+			wantRC:  "export ZSH=~/.oh-my-zsh\nsource $ZSH/oh-my-zsh.sh\nalias k=kubectl\n",
+		},
+	}
+	for _, step := range steps {
+		if step.setup != nil {
+			step.setup()
+		}
+		out, err := runInstall(t, home, nil, step.args...)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		if !strings.Contains(out, step.wantOut) {
+			t.Fatalf("%s: expected %q, got:\n%s", step.name, step.wantOut, out)
+		}
+		if got := readRC(); got != step.wantRC {
+			t.Fatalf("%s: .zshrc: want %q, got %q", step.name, step.wantRC, got)
+		}
+	}
+}
+
+func Test_InstallCommand_ZshFpathNewRC(t *testing.T) {
+	home := t.TempDir()
+	if _, err := runInstall(t, home, map[string]string{"ZDOTDIR": home}, "install", "zsh"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".zshrc"))
+	if err != nil {
+		t.Fatalf("expected a .zshrc: %v", err)
+	}
+	if string(data) != zshFpathLine+"\n" {
+		t.Fatalf("want the fpath entry alone, got %q", data)
+	}
+}
+
+func Test_InstallCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{name: "no $SHELL", args: []string{"install"}, want: "$SHELL is not set"},
+		{name: "unsupported $SHELL", env: map[string]string{"SHELL": "/bin/tcsh"}, args: []string{"install"}, want: `unsupported shell "tcsh"`},
+		{name: "unsupported shell", args: []string{"uninstall", "pwsh"}, want: `unsupported shell "pwsh"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runInstall(t, t.TempDir(), tt.env, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func Test_CompletionCommand_StillPrintsScripts(t *testing.T) {
+	out, err := runInstall(t, t.TempDir(), nil, "bash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "complete -o default -F _myapp_completions myapp") {
+		t.Fatalf("expected the bash script, got:\n%s", out)
+	}
+}
@@ -22,7 +22,7 @@ _{{.AppName}}_completions() {
     out=$(eval "${requestComp}" 2>/dev/null)
 
     directive="${out##*:}"
-    out="${out%%:*}"
+    out="${out%:*}"
     if [[ "${directive}" == "${out}" ]]; then
         directive=0
     fi
@@ -30,27 +30,54 @@ _{{.AppName}}_completions() {
     local shellCompDirectiveError=1
     local shellCompDirectiveNoSpace=2
     local shellCompDirectiveNoFileComp=4
+    local shellCompDirectiveFilterFileExt=8
+    local shellCompDirectiveFilterDirs=16
 
     if (( (directive & shellCompDirectiveError) != 0 )); then
         return
     fi
 
-    if (( (directive & shellCompDirectiveNoSpace) != 0 )); then
-        compopt -o nospace 2>/dev/null
-    fi
-
-    if (( (directive & shellCompDirectiveNoFileComp) != 0 )); then
-        compopt +o default 2>/dev/null
+    # "--flag=value": readline replaces only the text after the "=" (when "=" breaks words),
+    # so candidates are matched and returned without the flag part.
+    local value="${cur}" flagPrefix=""
+    if [[ ${cur} == -*=* ]]; then
+        flagPrefix="${cur%%=*}="
+        value="${cur#*=}"
     fi
 
     local completions=()
     while IFS='' read -r comp; do
         [[ -z "${comp}" ]] && continue
-        completions+=("${comp%%$'\t'*}")
+        comp="${comp%%$'\t'*}"
+        completions+=("${comp#"${flagPrefix}"}")
     done <<< "${out}"
 
-    IFS=$'\n' read -ra COMPREPLY -d '' \
-        < <(IFS=$'\n'; compgen -W "${completions[*]}" -- "${cur}")
+    local IFS=$'\n'
+    if (( (directive & shellCompDirectiveFilterFileExt) != 0 )); then
+        # the candidates are the extensions to keep; directories stay reachable
+        compopt -o filenames 2>/dev/null
+        COMPREPLY=($(compgen -d -- "${value}"))
+        local ext
+        for ext in "${completions[@]}"; do
+            COMPREPLY+=($(compgen -f -X "!*.${ext}" -- "${value}"))
+        done
+    elif (( (directive & shellCompDirectiveFilterDirs) != 0 )); then
+        compopt -o filenames 2>/dev/null
+        COMPREPLY=($(compgen -d -- "${value}"))
+    else
+        if (( (directive & shellCompDirectiveNoFileComp) != 0 )); then
+            compopt +o default 2>/dev/null
+        fi
+        COMPREPLY=($(compgen -W "${completions[*]}" -- "${value}"))
+    fi
+
+    if (( (directive & shellCompDirectiveNoSpace) != 0 )); then
+        compopt -o nospace 2>/dev/null
+    fi
+
+    if [[ -n ${flagPrefix} && ${COMP_WORDBREAKS} != *=* ]]; then
+        COMPREPLY=("${COMPREPLY[@]/#/${flagPrefix}}")
+    fi
 }
 
 complete -o default -F _{{.AppName}}_completions {{.AppName}}
@@ -1 +1,48 @@
-complete -c {{.AppName}} -f -a '({{.AppName}} __complete (commandline -cop) "" 2>/dev/null | string match -rv "^:" | string replace \\t \\t)'
+function __{{.AppName}}_complete
+    set -l token (commandline -ct)
+    set -l out ({{.AppName}} __complete (commandline -cop) "$token" 2>/dev/null)
+    set -l directive 0
+    if string match -qr '^:[0-9]+$' -- "$out[-1]"
+        set directive (string sub -s 2 -- $out[-1])
+        set -e out[-1]
+    end
+
+    # error
+    if test (math "$directive % 2") -eq 1
+        return
+    end
+
+    set -l nofile (math "floor($directive / 4) % 2")
+    set -l exts (math "floor($directive / 8) % 2")
+    set -l dirs (math "floor($directive / 16) % 2")
+
+    if test $exts -eq 0 -a $dirs -eq 0
+        if test (count $out) -gt 0
+            printf '%s\n' $out
+            return
+        else if test $nofile -eq 1
+            return
+        end
+    end
+
+    # path completion: "--flag=value" completes the value, keeping the flag in front
+    set -l prefix (string match -r -- '^-[^=]*=' "$token")
+    set -l value (string replace -r -- '^-[^=]*=' '' "$token")
+    set -l keep
+    if test $exts -eq 1
+        for comp in $out
+            set -a keep (string split -f1 \t -- $comp)
+        end
+    end
+    for path in $value*
+        if test -d $path
+            printf '%s%s/\n' "$prefix" $path
+        else if test $dirs -eq 0
+            if test $exts -eq 0; or contains -- (string match -r -- '[^.]*$' $path) $keep
+                printf '%s%s\n' "$prefix" $path
+            end
+        end
+    end
+end
+
+complete -c {{.AppName}} -f -a '(__{{.AppName}}_complete)'
@@ -0,0 +1,72 @@
+Register-ArgumentCompleter -Native -CommandName '{{.AppName}}' -ScriptBlock {
+    param($WordToComplete, $CommandAst, $CursorPosition)
+
+    $ShellCompDirectiveError = 1
+    $ShellCompDirectiveNoSpace = 2
+    $ShellCompDirectiveNoFileComp = 4
+    $ShellCompDirectiveFilterFileExt = 8
+    $ShellCompDirectiveFilterDirs = 16
+
+    # the words before the cursor, less the command itself, then the word being completed
+    $Words = @($CommandAst.CommandElements |
+        Where-Object { $_.Extent.EndOffset -le $CursorPosition } |
+        Select-Object -Skip 1 |
+        ForEach-Object { $_.Extent.Text })
+    if ($WordToComplete -eq '') {
+        # before 7.3, an empty argument is dropped on its way to a native command unless quoted
+        if ($PSVersionTable.PSVersion -lt [version]'7.3') { $Words += '""' } else { $Words += '' }
+    }
+
+    $Out = @(& '{{.AppName}}' __complete @Words 2>$null)
+    $Directive = 0
+    if ($Out.Count -gt 0 -and $Out[-1] -match '^:(\d+)$') {
+        $Directive = [int]$Matches[1]
+        $Out = @($Out | Select-Object -SkipLast 1)
+    }
+
+    if (($Directive -band $ShellCompDirectiveError) -ne 0) {
+        return
+    }
+
+    if (($Directive -band ($ShellCompDirectiveFilterFileExt -bor $ShellCompDirectiveFilterDirs)) -ne 0) {
+        # "--flag=value" completes the value, keeping the flag in front
+        $Prefix = ''
+        $Value = $WordToComplete
+        if ($WordToComplete -match '^(-[^=]*=)(.*)$') {
+            $Prefix = $Matches[1]
+            $Value = $Matches[2]
+        }
+        $Dir = $Value.Substring(0, $Value.LastIndexOfAny([char[]]'/\') + 1)
+        $Extensions = @($Out | Where-Object { $_ -ne '' } | ForEach-Object { '.' + ($_ -split "`t")[0] })
+        Get-ChildItem -Path "$Value*" -ErrorAction SilentlyContinue | Where-Object {
+            $_.PSIsContainer -or (
+                ($Directive -band $ShellCompDirectiveFilterDirs) -eq 0 -and $Extensions -contains $_.Extension
+            )
+        } | ForEach-Object {
+            $Path = $Dir + $_.Name
+            if ($_.PSIsContainer) { $Path += [IO.Path]::DirectorySeparatorChar }
+            [System.Management.Automation.CompletionResult]::new("$Prefix$Path", $_.Name, 'ProviderItem', $_.Name)
+        }
+        return
+    }
+
+    $Candidates = @($Out | Where-Object { $_ -ne '' } | ForEach-Object {
+        $Value, $Help = $_ -split "`t", 2
+        if (-not $Help) { $Help = $Value }
+        [pscustomobject]@{ Value = $Value; Help = $Help }
+    } | Where-Object { $_.Value -like "$WordToComplete*" })
+
+    if ($Candidates.Count -eq 0) {
+        if (($Directive -band $ShellCompDirectiveNoFileComp) -ne 0) {
+            # an empty result would fall back to path completion
+            [System.Management.Automation.CompletionResult]::new(' ', ' ', 'ParameterValue', ' ')
+        }
+        return
+    }
+
+    $Space = ' '
+    if (($Directive -band $ShellCompDirectiveNoSpace) -ne 0) { $Space = '' }
+    foreach ($Candidate in $Candidates) {
+        [System.Management.Automation.CompletionResult]::new($Candidate.Value + $Space, $Candidate.Value, 'ParameterValue', $Candidate.Help)
+    }
+}
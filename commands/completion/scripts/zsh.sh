@@ -30,6 +30,33 @@ _{{.AppName}}() {
         directive=0
     fi
 
+    local shellCompDirectiveError=1
+    local shellCompDirectiveNoSpace=2
+    local shellCompDirectiveNoFileComp=4
+    local shellCompDirectiveFilterFileExt=8
+    local shellCompDirectiveFilterDirs=16
+
+    if (( (directive & shellCompDirectiveError) != 0 )); then
+        return 1
+    fi
+
+    if (( (directive & shellCompDirectiveFilterFileExt) != 0 )); then
+        # the candidates are the extensions to keep; directories stay reachable
+        local -a globs
+        while IFS='\n' read -r comp; do
+            [[ -n "${comp}" ]] && globs+=("*.${comp%%$'\t'*}")
+        done < <(printf "%s\n" "${out[@]}")
+        compset -P '-*='
+        _files -g "(${(j:|:)globs})"
+        return
+    fi
+
+    if (( (directive & shellCompDirectiveFilterDirs) != 0 )); then
+        compset -P '-*='
+        _files -/
+        return
+    fi
+
     local -a descs
     while IFS='\n' read -r comp; do
         [[ -n "${comp}" ]] || continue
@@ -38,12 +65,18 @@ _{{.AppName}}() {
         descs+=("${comp}")
     done < <(printf "%s\n" "${out[@]}")
 
-    if _describe '' descs; then
+    local -a noSpace
+    if (( (directive & shellCompDirectiveNoSpace) != 0 )); then
+        noSpace=(-S '')
+    fi
+
+    if _describe '' descs "${noSpace[@]}"; then
         return 0
     fi
 
-    if (( (directive & 4) == 0 )); then
-        _arguments '*:filename:_files'
+    if (( (directive & shellCompDirectiveNoFileComp) == 0 )); then
+        compset -P '-*='
+        _files
     fi
 }
 
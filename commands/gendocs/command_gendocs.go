@@ -53,10 +53,11 @@ func (c *Command) Run(_ cli.GlobalFlags, _ cli.Unknowns) error {
 		return fmt.Errorf("failed to generate docs: %w", err)
 	}
 
+	out := c.IO().Out
 	for _, path := range written {
-		fmt.Printf("  %s\n", path)
+		fmt.Fprintf(out, "  %s\n", path)
 	}
-	fmt.Printf("\n%d files written to %s/\n", len(written), c.Inputs.OutputDir)
+	fmt.Fprintf(out, "\n%d files written to %s/\n", len(written), c.Inputs.OutputDir)
 	return nil
 }
 
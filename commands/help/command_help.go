@@ -2,8 +2,9 @@
 package help
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/toaweme/cli"
 	clihelp "github.com/toaweme/cli/help"
@@ -23,6 +24,7 @@ type Command struct {
 }
 
 var _ cli.Command[Config] = (*Command)(nil)
+var _ cli.ErrorDisplayer = (*Command)(nil)
 
 // NewHelpCommand creates a help command that lists all available commands.
 // The formats getter (typically App.OutputFormats) supplies the codecs registered via App.HelpOutputs
@@ -34,6 +36,24 @@ func NewHelpCommand(settingsFunc func() cli.Config, commandList func() []cli.Com
 
 // Run renders help output in the requested format for the app or a filtered command.
 func (c *Command) Run(options cli.GlobalFlags, unknowns cli.Unknowns) error {
+	return c.RunContext(context.Background(), options, unknowns)
+}
+
+// DisplayError writes err to w in the given --help-format (see clihelp.DisplayError), making the command a
+// cli.ErrorDisplayer: cli.App.Main reports a failed run through it.
+func (c *Command) DisplayError(w io.Writer, err error, format string) {
+	clihelp.DisplayError(w, err, format)
+}
+
+// RunContext is Run under ctx. When the App diverted to help because of a failure (see cli.HelpCause),
+// such as a mistyped command, the failure and its suggestions are written to stderr first - as JSON for the
+// json formats - so stdout carries only the help output. Both streams are the App's (see cli.App.IO).
+func (c *Command) RunContext(ctx context.Context, options cli.GlobalFlags, unknowns cli.Unknowns) error {
+	streams := c.IO()
+	if cause := cli.HelpCause(ctx); cause != nil {
+		clihelp.DisplayError(streams.Err, cause, options.HelpFormat)
+	}
+
 	cfg := c.settingsFunc()
 	commands := c.commandListFunc()
 	appName := cfg.Name
@@ -76,7 +96,7 @@ func (c *Command) Run(options cli.GlobalFlags, unknowns cli.Unknowns) error {
 	// every other registered codec renders the command tree.
 	if format != "json" && format != "jsonschema" {
 		if codec, ok := customCodecs[format]; ok {
-			if err := clihelp.DisplayHelpEncoded(os.Stdout, filtered, codec, options.HelpValues); err != nil {
+			if err := clihelp.DisplayHelpEncoded(streams.Out, filtered, codec, options.HelpValues); err != nil {
 				return fmt.Errorf("failed to display help as %q: %w", format, err)
 			}
 			return nil
@@ -85,13 +105,13 @@ func (c *Command) Run(options cli.GlobalFlags, unknowns cli.Unknowns) error {
 
 	switch format {
 	case "json":
-		clihelp.DisplayHelpJSON(os.Stdout, filtered, options.HelpValues)
+		clihelp.DisplayHelpJSON(streams.Out, filtered, options.HelpValues)
 		return nil
 	case "jsonschema":
-		clihelp.DisplayHelpJSONSchema(os.Stdout, filtered, options.HelpValues)
+		clihelp.DisplayHelpJSONSchema(streams.Out, filtered, options.HelpValues)
 		return nil
 	case "pretty", "plain", "md":
-		clihelp.DisplayHelpAgent(os.Stdout, clihelp.AgentOptions{
+		clihelp.DisplayHelpAgent(streams.Out, clihelp.AgentOptions{
 			AppName:        appName,
 			Format:         format,
 			Commands:       filtered,
@@ -102,7 +122,7 @@ func (c *Command) Run(options cli.GlobalFlags, unknowns cli.Unknowns) error {
 		})
 		return nil
 	case "plain-flags":
-		clihelp.DisplayHelp(os.Stdout, appName, commands, unknowns.Args, clihelp.DisplayOptions{
+		clihelp.DisplayHelp(streams.Out, appName, commands, unknowns.Args, clihelp.DisplayOptions{
 			ShowFlags:      true,
 			ShowEnv:        true,
 			ShowValues:     options.HelpValues,
@@ -113,7 +133,7 @@ func (c *Command) Run(options cli.GlobalFlags, unknowns cli.Unknowns) error {
 		return nil
 	}
 
-	clihelp.DisplayHelp(os.Stdout, appName, commands, unknowns.Args, clihelp.DisplayOptions{
+	clihelp.DisplayHelp(streams.Out, appName, commands, unknowns.Args, clihelp.DisplayOptions{
 		ShowValues:     options.HelpValues,
 		GlobalValues:   &options,
 		Formats:        formatNames,
@@ -241,3 +241,23 @@ func Test_ParentCommand_Add_RegistersSubCommand(t *testing.T) {
 		t.Fatalf("expected subcommand name %q, got %q", "child", subs[0].Name(""))
 	}
 }
+
+func Test_HelpCommand_WritesToAppIO(t *testing.T) {
+	t.Parallel()
+
+	var out, errOut bytes.Buffer
+	app := cli.NewApp(cli.Config{Name: "myapp"}, cli.GlobalFlags{}).IO(cli.IO{Out: &out, Err: &errOut})
+	app.Help(NewHelpCommand(app.Config, app.Commands, app.OutputFormats, app.DefaultCommand))
+	app.Add("build", newStub("build", "Build the project"))
+
+	err := app.Run([]string{"biuld"})
+	if !errors.Is(err, cli.ErrShowingHelp) {
+		t.Fatalf("expected help to be shown, got %v", err)
+	}
+	if !strings.Contains(out.String(), "Build the project") {
+		t.Fatalf("expected help on the app's stdout, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "did you mean 'build'") {
+		t.Fatalf("expected the error on the app's stderr, got %q", errOut.String())
+	}
+}
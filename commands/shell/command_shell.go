@@ -0,0 +1,182 @@
+// Package shell provides a command that runs an interactive session of the app's own commands.
+package shell
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/toaweme/cli"
+)
+
+// exitCommand ends the session, like Ctrl-D.
+const exitCommand = "exit"
+
+// historyLimit is how many of the most recent history lines are loaded.
+const historyLimit = 1000
+
+// Config holds the inputs for the shell command.
+type Config struct {
+	// History is the file lines are saved to and recalled from, ~/.<app>_history when unset.
+	History string `arg:"history" help:"History file (default: ~/.<app>_history)"`
+}
+
+// Command runs a read-eval loop over the app's commands: each line is split like a shell would and
+// dispatched through App.RunContext, so it parses, merges, validates, and runs exactly as the same
+// arguments would from the command line, starting from fresh inputs and global flags every time.
+type Command struct {
+	cli.BaseCommand[Config]
+
+	app cli.App
+}
+
+var _ cli.Command[Config] = (*Command)(nil)
+
+// NewShellCommand creates a shell command dispatching lines to app, the app it is added to.
+func NewShellCommand(app cli.App) *Command {
+	return &Command{
+		BaseCommand: cli.NewBaseCommand[Config](),
+		app:         app,
+	}
+}
+
+// Run reads and runs lines until `exit`, Ctrl-D, or the end of input. A failing line prints its error
+// and the session goes on. Each line runs under its own context (see cli.InterruptContext): Ctrl-C
+// during a command cancels that line only and returns to the prompt, while a cancelled run context,
+// such as on SIGTERM, ends the session.
+func (c *Command) Run(_ cli.GlobalFlags, _ cli.Unknowns) error {
+	name := c.app.Config().Name
+	path := ""
+	if c.Inputs != nil {
+		path = c.Inputs.History
+	}
+	if path == "" {
+		path = defaultHistoryPath(name)
+	}
+
+	streams := c.IO()
+	ed := newEditor(streams, loadHistory(path), c.app.Complete)
+	prompt := name + "> "
+	for {
+		line, err := ed.readLine(prompt)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if errors.Is(err, errInterrupt) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read line: %w", err)
+		}
+
+		args, err := Split(line)
+		if err != nil {
+			fmt.Fprintf(streams.Err, "error: %v\n", err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		ed.remember(line)
+		if err := appendHistory(path, line); err != nil {
+			fmt.Fprintf(streams.Err, "warning: failed to save history: %v\n", err)
+		}
+
+		switch args[0] {
+		case exitCommand:
+			return nil
+		case c.Name(""):
+			fmt.Fprintln(streams.Err, "error: already in a shell")
+			continue
+		}
+
+		ctx := c.Context()
+		lineCtx, stop := cli.InterruptContext(ctx)
+		err = c.app.RunContext(lineCtx, args)
+		interrupted := lineCtx.Err() != nil && ctx.Err() == nil
+		stop()
+		switch {
+		case ctx.Err() != nil:
+			return context.Cause(ctx)
+		case interrupted:
+			// the terminal echoed ^C; end its line and leave the command's error unsaid
+			fmt.Fprintln(streams.Err)
+		case cli.IsRealError(err):
+			fmt.Fprintf(streams.Err, "error: %v\n", err)
+		}
+	}
+}
+
+// Help returns the one-line help summary for the command.
+func (c *Command) Help() string {
+	return "Start an interactive shell"
+}
+
+// Description returns the long-form description shown in help output.
+func (c *Command) Description() string {
+	return strings.Join([]string{
+		"Read commands line by line and run each as if given on the command line.",
+		"",
+		"Lines are split like a shell splits them (quotes and backslashes, no expansion).",
+		"Up/down recall history, tab completes commands and flags.",
+		"Type exit or press Ctrl-D to leave.",
+	}, "\n")
+}
+
+// defaultHistoryPath is ~/.<app>_history, or "" (no history) without a home directory.
+func defaultHistoryPath(appName string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || appName == "" {
+		return ""
+	}
+
+	return filepath.Join(home, "."+appName+"_history")
+}
+
+// loadHistory returns the last historyLimit lines of the history file at path; none when it is unset or unreadable.
+func loadHistory(path string) []string {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > historyLimit {
+		lines = lines[len(lines)-historyLimit:]
+	}
+
+	return lines
+}
+
+// appendHistory adds line to the history file at path, as soon as it is entered, so a session that
+// ends abruptly keeps it.
+func appendHistory(path, line string) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,233 @@
+package shell
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/toaweme/cli"
+)
+
+type greetConfig struct {
+	Name string `arg:"name" help:"Who to greet"`
+	Loud bool   `arg:"loud" help:"Shout"`
+}
+
+type greetCommand struct {
+	cli.BaseCommand[greetConfig]
+}
+
+func (c *greetCommand) Help() string { return "Say hello" }
+func (c *greetCommand) Run(g cli.GlobalFlags, _ cli.Unknowns) error {
+	fmt.Fprintf(c.IO().Out, "name=%q loud=%v cwd=%q\n", c.Inputs.Name, c.Inputs.Loud, g.Cwd)
+	return nil
+}
+
+type readConfig struct{}
+
+// readCommand reads one line of the app's input, as a command asking a question does.
+type readCommand struct {
+	cli.BaseCommand[readConfig]
+}
+
+func (c *readCommand) Help() string { return "Read a line" }
+func (c *readCommand) Run(_ cli.GlobalFlags, _ cli.Unknowns) error {
+	line, err := c.IO().Reader().ReadString('\n')
+	fmt.Fprintf(c.IO().Out, "read=%q\n", strings.TrimSuffix(line, "\n"))
+	return err
+}
+
+func newShellApp(in string) (cli.App, *bytes.Buffer, *bytes.Buffer) {
+	var out, errOut bytes.Buffer
+	app := cli.NewApp(cli.Config{Name: "tool"}, cli.GlobalFlags{})
+	app.IO(cli.IO{In: strings.NewReader(in), Out: &out, Err: &errOut, Env: cli.MapEnv(nil)})
+	app.Add("greet", &greetCommand{BaseCommand: cli.NewBaseCommand[greetConfig]()})
+	app.Add("read", &readCommand{BaseCommand: cli.NewBaseCommand[readConfig]()})
+	app.Add("shell", NewShellCommand(app))
+	return app, &out, &errOut
+}
+
+func Test_ShellCommand_Run(t *testing.T) {
+	history := filepath.Join(t.TempDir(), "history")
+	app, out, errOut := newShellApp("--cwd /tmp greet --name 'Ada L' --loud\n\ngreet\ngreet --name \"unterminated\nshell\nexit\ngreet\n")
+
+	if err := app.Run([]string{"shell", "--history", history}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, `name="Ada L" loud=true cwd="/tmp"`) {
+		t.Fatalf("expected the first line to run with its flags, got:\n%s", got)
+	}
+	if !strings.Contains(got, `tool> name="" loud=false cwd=""`) {
+		t.Fatalf("expected the second line to start from fresh inputs and globals, got:\n%s", got)
+	}
+	if strings.Count(got, "name=") != 2 {
+		t.Fatalf("expected the session to end at exit, got:\n%s", got)
+	}
+	if !strings.Contains(errOut.String(), "unterminated quote") || !strings.Contains(errOut.String(), "already in a shell") {
+		t.Fatalf("expected per-line errors, got:\n%s", errOut.String())
+	}
+
+	data, err := os.ReadFile(history)
+	if err != nil {
+		t.Fatalf("expected a history file: %v", err)
+	}
+	if want := "--cwd /tmp greet --name 'Ada L' --loud\ngreet\nshell\nexit\n"; string(data) != want {
+		t.Fatalf("history: want %q, got %q", want, string(data))
+	}
+}
+
+func Test_ShellCommand_EOF(t *testing.T) {
+	app, out, _ := newShellApp("greet --name x")
+
+	if err := app.Run([]string{"shell", "--history", filepath.Join(t.TempDir(), "history")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `name="x"`) {
+		t.Fatalf("expected the last line to run before the end of input, got:\n%s", out.String())
+	}
+}
+
+func Test_ShellCommand_SharesInput(t *testing.T) {
+	app, out, errOut := newShellApp("read\nanswer\ngreet\nexit\n")
+
+	if err := app.Run([]string{"shell", "--history", filepath.Join(t.TempDir(), "history")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `read="answer"`) {
+		t.Fatalf("expected the command to read the line after its own, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), `name=""`) || errOut.Len() != 0 {
+		t.Fatalf("expected the shell to go on after the line read, got:\n%s\n%s", out.String(), errOut.String())
+	}
+}
+
+func Test_Editor_ReadLine(t *testing.T) {
+	app, _, _ := newShellApp("")
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		err   error
+	}{
+		{name: "completes a command", input: "gr\t\r", want: "greet "},
+		{name: "completes a flag", input: "greet --lo\t\r", want: "greet --loud "},
+		{name: "backspace and cursor moves", input: "grx\x7fet\x1b[Da\x1b[F!\r", want: "great!"},
+		{name: "history up", input: "\x1b[A\x1b[A\r", want: "first"},
+		{name: "history down to the draft", input: "dra\x1b[A\x1b[Bft\r", want: "draft"},
+		{name: "ctrl-u clears", input: "junk\x15ok\r", want: "ok"},
+		{name: "ctrl-c abandons", input: "junk\x03", err: errInterrupt},
+		{name: "ctrl-d at empty line", input: "\x04", err: io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			ed := &editor{
+				in:       bufio.NewReader(strings.NewReader(tt.input)),
+				out:      &out,
+				edit:     true,
+				history:  []string{"first", "second"},
+				complete: app.Complete,
+			}
+			got, err := ed.readLine("> ")
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v, got %v", tt.err, err)
+			}
+			if got != tt.want {
+				t.Fatalf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_Editor_ListsAmbiguousCompletions(t *testing.T) {
+	var out bytes.Buffer
+	ed := &editor{
+		in:   bufio.NewReader(strings.NewReader("greet --\t\r")),
+		out:  &out,
+		edit: true,
+		complete: func(_ []string) []cli.Completion {
+			return []cli.Completion{{Value: "--name"}, {Value: "--loud"}}
+		},
+	}
+	if _, err := ed.readLine("> "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "--name  --loud") {
+		t.Fatalf("expected the candidates listed, got %q", out.String())
+	}
+}
+
+func Test_CommonPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{name: "ascii", values: []string{"--name", "--namespace"}, want: "--name"},
+		{name: "runes sharing a lead byte", values: []string{"café", "cafè"}, want: "caf"},
+		{name: "multi-byte prefix", values: []string{"日本語", "日本人"}, want: "日本"},
+		{name: "nothing shared", values: []string{"é", "è"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := commonPrefix(tt.values)
+			if got != tt.want {
+				t.Fatalf("want %q, got %q", tt.want, got)
+			}
+			if !utf8.ValidString(got) {
+				t.Fatalf("expected a whole-rune prefix, got %q", got)
+			}
+		})
+	}
+}
+
+func Test_Split(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+		err  bool
+	}{
+		{line: "", want: nil},
+		{line: "  deploy   prod ", want: []string{"deploy", "prod"}},
+		{line: `deploy --note "it's done"`, want: []string{"deploy", "--note", "it's done"}},
+		{line: `echo 'a "b" \c'`, want: []string{"echo", `a "b" \c`}},
+		{line: `echo "a \"b\" \c \$x"`, want: []string{"echo", `a "b" \c $x`}},
+		{line: `echo a\ b ""`, want: []string{"echo", "a b", ""}},
+		{line: `echo "open`, err: true},
+		{line: `echo trailing\`, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := Split(tt.line)
+			if tt.err {
+				if !errors.Is(err, ErrUnterminated) {
+					t.Fatalf("want ErrUnterminated, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("want %q, got %q", tt.want, got)
+			}
+			for _, word := range got {
+				if back, _ := Split(Quote(word)); len(back) != 1 || back[0] != word {
+					t.Fatalf("Quote(%q) does not split back, got %q", word, back)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+//go:build unix
+
+package shell
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/toaweme/cli"
+)
+
+type waitConfig struct{}
+
+// waitCommand sends the process a SIGINT, as Ctrl-C on the terminal would, and waits to be cancelled.
+type waitCommand struct {
+	cli.BaseCommand[waitConfig]
+}
+
+func (c *waitCommand) Help() string { return "Wait for Ctrl-C" }
+func (c *waitCommand) Run(_ cli.GlobalFlags, _ cli.Unknowns) error {
+	ctx := c.Context()
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	fmt.Fprintf(c.IO().Out, "cancelled: %v\n", context.Cause(ctx))
+	return ctx.Err()
+}
+
+func Test_ShellCommand_InterruptEndsTheLineOnly(t *testing.T) {
+	app, out, errOut := newShellApp("wait\ngreet --name after\n")
+	app.Add("wait", &waitCommand{BaseCommand: cli.NewBaseCommand[waitConfig]()})
+
+	if err := app.Run([]string{"shell", "--history", filepath.Join(t.TempDir(), "history")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "cancelled: interrupted: interrupt") {
+		t.Fatalf("expected the line to be interrupted, got:\n%s", got)
+	}
+	if !strings.Contains(got, `name="after"`) {
+		t.Fatalf("expected the session to go on after the interrupt, got:\n%s", got)
+	}
+	if strings.Contains(errOut.String(), "error:") {
+		t.Fatalf("expected no error for the interrupted line, got:\n%s", errOut.String())
+	}
+}
@@ -0,0 +1,250 @@
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/toaweme/cli"
+	"github.com/toaweme/cli/internal/term"
+)
+
+// errInterrupt is returned by readLine for a line abandoned with Ctrl-C.
+var errInterrupt = errors.New("interrupt")
+
+// Control keys the editor handles.
+const (
+	keyCtrlA     = 1
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyBackspace = 8
+	keyTab       = '\t'
+	keyEnter     = '\r'
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// editor reads lines for the shell. On a terminal it edits them key by key, with history (up/down),
+// cursor movement, and tab completion; anywhere else it reads plain lines.
+type editor struct {
+	in  *bufio.Reader
+	out io.Writer
+	// term is the terminal put into raw mode while a line is edited, nil when in is not one.
+	term *os.File
+	// edit enables key-by-key editing.
+	edit     bool
+	history  []string
+	complete func(args []string) []cli.Completion
+}
+
+// newEditor returns an editor reading from streams.In through streams.Reader, so the commands it runs
+// can read the same input, and echoing to streams.Out. It edits key by key when In is a terminal.
+func newEditor(streams cli.IO, history []string, complete func(args []string) []cli.Completion) *editor {
+	e := &editor{in: streams.Reader(), out: streams.Out, history: history, complete: complete}
+	if f, ok := streams.In.(*os.File); ok && term.IsTerminal(f) {
+		e.term = f
+		e.edit = true
+	}
+
+	return e
+}
+
+// remember adds line to the history recalled with up/down, unless it repeats the last one.
+func (e *editor) remember(line string) {
+	if n := len(e.history); n > 0 && e.history[n-1] == line {
+		return
+	}
+	e.history = append(e.history, line)
+}
+
+// readLine shows prompt and returns the line entered, io.EOF on Ctrl-D at an empty line or the end
+// of input, and errInterrupt on Ctrl-C.
+func (e *editor) readLine(prompt string) (string, error) {
+	if e.edit && e.term != nil {
+		state, err := term.MakeRaw(e.term)
+		if err != nil {
+			// a terminal that can't be put into raw mode (off Unix) is read a line at a time
+			e.edit = false
+		} else {
+			defer func() { _ = term.Restore(e.term, state) }()
+		}
+	}
+	if !e.edit {
+		fmt.Fprint(e.out, prompt)
+		line, err := e.in.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	var (
+		buf   []rune
+		pos   int
+		index = len(e.history)
+		draft []rune
+	)
+	e.redraw(prompt, buf, pos)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			if len(buf) > 0 && errors.Is(err, io.EOF) {
+				fmt.Fprint(e.out, "\r\n")
+				return string(buf), nil
+			}
+			return "", err
+		}
+
+		switch r {
+		case keyEnter, '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(buf), nil
+		case keyCtrlD:
+			if len(buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(buf) {
+				buf = append(buf[:pos], buf[pos+1:]...)
+			}
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupt
+		case keyBackspace, keyDelete:
+			if pos > 0 {
+				buf = append(buf[:pos-1], buf[pos:]...)
+				pos--
+			}
+		case keyCtrlA:
+			pos = 0
+		case keyCtrlE:
+			pos = len(buf)
+		case keyCtrlU:
+			buf = buf[pos:]
+			pos = 0
+		case keyTab:
+			buf, pos = e.completeAt(prompt, buf, pos)
+		case keyEscape:
+			switch e.escape() {
+			case 'A':
+				if index > 0 {
+					if index == len(e.history) {
+						draft = buf
+					}
+					index--
+					buf = []rune(e.history[index])
+					pos = len(buf)
+				}
+			case 'B':
+				if index < len(e.history) {
+					index++
+					if index == len(e.history) {
+						buf = draft
+					} else {
+						buf = []rune(e.history[index])
+					}
+					pos = len(buf)
+				}
+			case 'C':
+				if pos < len(buf) {
+					pos++
+				}
+			case 'D':
+				if pos > 0 {
+					pos--
+				}
+			case 'H':
+				pos = 0
+			case 'F':
+				pos = len(buf)
+			}
+		default:
+			if r >= ' ' {
+				buf = append(buf[:pos], append([]rune{r}, buf[pos:]...)...)
+				pos++
+			}
+		}
+		e.redraw(prompt, buf, pos)
+	}
+}
+
+// escape reads the rest of an ANSI escape sequence ("\x1b[A") and returns its final letter,
+// or 0 for one the editor doesn't handle.
+func (e *editor) escape() rune {
+	if r, _, err := e.in.ReadRune(); err != nil || (r != '[' && r != 'O') {
+		return 0
+	}
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return 0
+		}
+		if r >= '@' && r <= '~' {
+			return r
+		}
+	}
+}
+
+// redraw repaints the line with the cursor at pos.
+func (e *editor) redraw(prompt string, buf []rune, pos int) {
+	fmt.Fprintf(e.out, "\r\x1b[K%s%s", prompt, string(buf))
+	if back := len(buf) - pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// completeAt completes the word before pos: a single candidate is filled in, several are extended to
+// their common prefix, and listed when that adds nothing.
+func (e *editor) completeAt(prompt string, buf []rune, pos int) ([]rune, int) {
+	before := string(buf[:pos])
+	args, err := Split(before)
+	if err != nil || e.complete == nil {
+		return buf, pos
+	}
+	if before == "" || strings.ContainsRune(" \t", rune(before[len(before)-1])) {
+		args = append(args, "")
+	}
+	word := args[len(args)-1]
+
+	var values []string
+	for _, candidate := range e.complete(args) {
+		if strings.HasPrefix(candidate.Value, word) {
+			values = append(values, candidate.Value)
+		}
+	}
+	if len(values) == 0 {
+		fmt.Fprint(e.out, "\a")
+		return buf, pos
+	}
+
+	insert := commonPrefix(values)[len(word):]
+	if len(values) == 1 {
+		insert += " "
+	} else if insert == "" {
+		fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(values, "  "))
+		return buf, pos
+	}
+	insertion := []rune(insert)
+	buf = append(buf[:pos], append(insertion, buf[pos:]...)...)
+
+	return buf, pos + len(insertion)
+}
+
+// commonPrefix returns the longest prefix shared by all of values, cut at a rune boundary.
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+
+	return prefix
+}
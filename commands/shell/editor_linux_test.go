@@ -0,0 +1,32 @@
+package shell
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/toaweme/cli"
+	"github.com/toaweme/cli/internal/termtest"
+)
+
+func Test_Editor_RawModeOnlyWhileEditing(t *testing.T) {
+	ptm, pts := termtest.OpenPTY(t)
+	var out bytes.Buffer
+	ed := newEditor(cli.IO{In: pts, Out: &out}, nil, nil)
+	if !ed.edit {
+		t.Fatal("expected a terminal to be edited key by key")
+	}
+
+	if _, err := ptm.WriteString("greet\r"); err != nil {
+		t.Fatal(err)
+	}
+	line, err := ed.readLine("> ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if line != "greet" {
+		t.Fatalf("want %q, got %q", "greet", line)
+	}
+	if !termtest.Echo(t, pts) || !termtest.Canonical(t, pts) {
+		t.Fatal("expected the terminal's mode restored once the line is read")
+	}
+}
@@ -0,0 +1,18 @@
+package shell
+
+import "github.com/toaweme/cli"
+
+// ErrUnterminated is returned by Split for a line ending inside quotes or after a lone backslash.
+var ErrUnterminated = cli.ErrUnterminatedQuote
+
+// Split breaks line into words the way a POSIX shell does, without expansion (see cli.SplitArgs).
+// `deploy --note "it's done"` is ["deploy", "--note", "it's done"].
+func Split(line string) ([]string, error) {
+	return cli.SplitArgs(line)
+}
+
+// Quote returns word as Split reads it back: unchanged when it needs no quoting,
+// single-quoted otherwise.
+func Quote(word string) string {
+	return cli.QuoteArg(word)
+}
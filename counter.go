@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/toaweme/structs"
+)
+
+// tagCount marks an int field as a counter: count:"true" makes each bare mention of its flag add one,
+// so "-v -v", "-vv", and "--verbose --verbose" all give 2, while "--verbose=2" sets it outright.
+const tagCount = "count"
+
+// FieldCounter reports whether field is a counter flag (an int tagged count:"true"), which help shows as repeatable.
+func FieldCounter(field structs.Field) bool {
+	return field.Tags[tagCount] == "true" && strings.HasPrefix(field.Type, "int")
+}
+
+// counterKey is the option name a counter's mentions add up under, whichever of its names was used:
+// the (fully-qualified) arg, else the short.
+func counterKey(field structs.Field, name string) string {
+	if arg := fieldTag(field, tagArg); arg != "" {
+		return arg
+	}
+
+	return name
+}
+
+// addCount adds n to the counter field's running total in options.
+func addCount(options map[string]any, field structs.Field, name string, n int) {
+	key := counterKey(field, name)
+	total, _ := options[key].(int)
+	options[key] = total + n
+}
+
+// setCount sets the counter field to value as written ("--verbose=2"), which later mentions add to.
+// A value that isn't a number is kept as written for the field's own parsing to reject.
+func setCount(options map[string]any, field structs.Field, name, value string) {
+	key := counterKey(field, name)
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		options[key] = value
+		return
+	}
+	options[key] = n
+}
+
+// matchRepeatedShort resolves a run of one counter's short, "vvv", to that field and the run's length;
+// nil when name is not such a run.
+func matchRepeatedShort(fields []structs.Field, name string) (*structs.Field, int) {
+	first, size := utf8.DecodeRuneInString(name)
+	if size == 0 || strings.Trim(name, string(first)) != "" {
+		return nil, 0
+	}
+	field := matchField(fields, string(first))
+	if field == nil || !FieldCounter(*field) || field.Tags[tagShort] != string(first) {
+		return nil, 0
+	}
+
+	return field, utf8.RuneCountInString(name)
+}
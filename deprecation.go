@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/toaweme/structs"
+)
+
+// tagDeprecated and tagReplacement mark a config field as deprecated:
+// deprecated holds the message shown when the flag or env var is used,
+// replacement names the arg of the field that supersedes it (e.g. "region" or "database.host").
+// A deprecated field keeps working - its value is forwarded to the replacement during the merge.
+const (
+	tagDeprecated  = "deprecated"
+	tagReplacement = "replacement"
+)
+
+// Deprecation describes a deprecated command or config field. The zero value means not deprecated.
+type Deprecation struct {
+	// Message explains the deprecation (e.g. "will be removed in v2").
+	Message string
+	// Replacement is what to use instead: a command path for a command, a flag name (without dashes) for a field.
+	Replacement string
+}
+
+// IsDeprecated reports whether d marks anything as deprecated.
+func (d Deprecation) IsDeprecated() bool {
+	return d.Message != "" || d.Replacement != ""
+}
+
+// deprecationProvider is satisfied by every command embedding BaseCommand, so a command can be deprecated
+// without widening the Command interface.
+type deprecationProvider interface {
+	Deprecated() Deprecation
+}
+
+// CommandDeprecation returns cmd's deprecation (see BaseCommand.Deprecate), the zero Deprecation when it
+// has none. Running a deprecated command prints a one-time warning, and help listings leave it out
+// (JSON help keeps it, marked).
+func CommandDeprecation(cmd Command[any]) Deprecation {
+	if p, ok := cmd.(deprecationProvider); ok {
+		return p.Deprecated()
+	}
+
+	return Deprecation{}
+}
+
+// FieldDeprecation reads the deprecation declared on a config field by its deprecated/replacement tags.
+// A field is deprecated when its deprecated tag carries a message.
+func FieldDeprecation(field structs.Field) Deprecation {
+	if field.Tags[tagDeprecated] == "" {
+		return Deprecation{}
+	}
+
+	return Deprecation{Message: field.Tags[tagDeprecated], Replacement: field.Tags[tagReplacement]}
+}
+
+// deprecationWarning renders the warning for the deprecated kind ("command", "flag", "env") named name,
+// e.g. "flag '--old' is deprecated, use '--new' instead: will be removed in v2". replacement is the
+// replacement as the user would type it, empty when there is none.
+func deprecationWarning(kind, name, replacement, message string) string {
+	msg := fmt.Sprintf("%s '%s' is deprecated", kind, name)
+	if replacement != "" {
+		msg += fmt.Sprintf(", use '%s' instead", replacement)
+	}
+	if message != "" {
+		msg += ": " + message
+	}
+
+	return msg
+}
+
+// warnDeprecated prints msg to stderr as a warning, at most once per App: a REPL or a test
+// dispatching repeatedly keeps its output readable.
+func (c *app) warnDeprecated(msg string) {
+	if c.deprecationsWarned == nil {
+		c.deprecationsWarned = make(map[string]bool)
+	}
+	if c.deprecationsWarned[msg] {
+		return
+	}
+	c.deprecationsWarned[msg] = true
+	fmt.Fprintf(c.stdio().Err, "warning: %s\n", msg)
+}
+
+// warnDeprecatedCommands warns about each deprecated command along the dispatched chain.
+func (c *app) warnDeprecatedCommands(chain []Command[any]) {
+	var path []string
+	for _, cmd := range chain {
+		path = append(path, cmd.Name(""))
+		if dep := CommandDeprecation(cmd); dep.IsDeprecated() {
+			c.warnDeprecated(deprecationWarning("command", strings.Join(path, " "), dep.Replacement, dep.Message))
+		}
+	}
+}
+
+// forwardDeprecated warns about each deprecated field set by a flag or env var and, when the field names
+// a replacement, copies the value over to it: a deprecated flag lands in flags under the replacement's name,
+// a deprecated env var in values (the resolved layer), so each keeps its own precedence. The replacement's own
+// flag or env var, when also set, wins over the forwarded value. Resolving for --help-values forwards
+// without warning, as the command doesn't run.
+func (c *app) forwardDeprecated(fields []structs.Field, values, flags map[string]any) {
+	warn := c.warnDeprecated
+	if c.globalFlags.Help {
+		warn = func(string) {}
+	}
+	for _, field := range deprecatedFields(fields) {
+		dep := FieldDeprecation(field)
+		var replacement *structs.Field
+		if dep.Replacement != "" {
+			replacement = matchField(fields, dep.Replacement)
+		}
+
+		if value, ok := flagValue(fields, flags, field); ok {
+			warn(deprecationWarning("flag", "--"+fieldFlagName(field), flagDisplay(dep.Replacement), dep.Message))
+			if replacement != nil {
+				if _, set := flagValue(fields, flags, *replacement); !set {
+					flags[dep.Replacement] = value
+				}
+			}
+		}
+
+		if name, value, ok := envValue(c.stdio().Env, field); ok {
+			warn(deprecationWarning("env", name, envDisplay(replacement, dep.Replacement), dep.Message))
+			if replacement != nil {
+				if _, _, set := envValue(c.stdio().Env, *replacement); !set {
+					values[dep.Replacement] = value
+				}
+			}
+		}
+	}
+}
+
+// deprecatedFields returns the deprecated fields among fields and their nested fields.
+func deprecatedFields(fields []structs.Field) []structs.Field {
+	var deprecated []structs.Field
+	for _, field := range fields {
+		if FieldDeprecation(field).IsDeprecated() {
+			deprecated = append(deprecated, field)
+		}
+		deprecated = append(deprecated, deprecatedFields(field.Fields)...)
+	}
+
+	return deprecated
+}
+
+// flagValue returns the value flags holds for field, under whichever of its names (long, short, dotted) was typed.
+func flagValue(fields []structs.Field, flags map[string]any, field structs.Field) (any, bool) {
+	for key, value := range flags {
+		if matched := matchField(fields, key); matched != nil && sameField(*matched, field) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// envValue returns the name and value of the env var bound to field, when one is set in env.
+// A nested field is looked up by its own env tag and by its prefixed one.
+func envValue(env Environment, field structs.Field) (string, string, bool) {
+	names := []string{field.Tags["env"]}
+	if field.FQN != nil {
+		names = append(names, field.FQN.Tags["env"])
+	}
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if value, ok := env.LookupEnv(name); ok {
+			return name, value, true
+		}
+	}
+
+	return "", "", false
+}
+
+// sameField reports whether a and b are the same struct field; nested fields share Go names across parents,
+// so their dotted name tells them apart.
+func sameField(a, b structs.Field) bool {
+	return a.Name == b.Name && fieldFlagName(a) == fieldFlagName(b)
+}
+
+// fieldFlagName is the long flag name field is typed as: its dotted name when nested, else its arg (or short) tag.
+func fieldFlagName(field structs.Field) string {
+	if field.FQN != nil && field.FQN.Tags[tagArg] != "" {
+		return field.FQN.Tags[tagArg]
+	}
+	if field.Tags[tagArg] != "" {
+		return field.Tags[tagArg]
+	}
+
+	return field.Tags[tagShort]
+}
+
+// flagDisplay renders a replacement flag name as typed ("--region"), or "" when there is none.
+func flagDisplay(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	return "--" + name
+}
+
+// envDisplay renders the replacement for a deprecated env var: the replacement field's env var when it has one,
+// else its flag.
+func envDisplay(replacement *structs.Field, name string) string {
+	if replacement != nil && replacement.Tags["env"] != "" {
+		return replacement.Tags["env"]
+	}
+
+	return flagDisplay(name)
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type depConfig struct {
+	Region    string `arg:"region" env:"DEP_REGION" help:"Region"`
+	OldRegion string `arg:"old-region" short:"o" env:"DEP_OLD_REGION" deprecated:"renamed in v2" replacement:"region"`
+	Legacy    bool   `arg:"legacy" deprecated:"no longer needed"`
+}
+
+type depCommand struct {
+	BaseCommand[depConfig]
+	got *depConfig
+}
+
+var _ Command[depConfig] = (*depCommand)(nil)
+
+func (c *depCommand) Help() string { return "dep" }
+func (c *depCommand) Run(_ GlobalFlags, _ Unknowns) error {
+	*c.got = *c.Inputs
+	return nil
+}
+
+func newDepApp(got *depConfig) (App, *depCommand) {
+	cmd := &depCommand{BaseCommand: NewBaseCommand[depConfig](), got: got}
+	app := NewApp(Config{Name: "app"}, GlobalFlags{})
+	app.Add("help", NewMockCommand(func() error { return nil }))
+	app.Add("deploy", cmd)
+	return app, cmd
+}
+
+func Test_Deprecated_FlagForwardsToReplacement(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		env        map[string]string
+		wantRegion string
+		wantWarn   string
+	}{
+		{
+			name:       "long flag",
+			args:       []string{"--old-region", "eu"},
+			wantRegion: "eu",
+			wantWarn:   "warning: flag '--old-region' is deprecated, use '--region' instead: renamed in v2\n",
+		},
+		{
+			name:       "short flag",
+			args:       []string{"-o", "eu"},
+			wantRegion: "eu",
+			wantWarn:   "warning: flag '--old-region' is deprecated, use '--region' instead: renamed in v2\n",
+		},
+		{
+			name:       "replacement flag wins",
+			args:       []string{"--old-region", "eu", "--region", "us"},
+			wantRegion: "us",
+			wantWarn:   "warning: flag '--old-region' is deprecated, use '--region' instead: renamed in v2\n",
+		},
+		{
+			name:       "env var",
+			env:        map[string]string{"DEP_OLD_REGION": "apac"},
+			wantRegion: "apac",
+			wantWarn:   "warning: env 'DEP_OLD_REGION' is deprecated, use 'DEP_REGION' instead: renamed in v2\n",
+		},
+		{
+			name:       "replacement env var wins",
+			env:        map[string]string{"DEP_OLD_REGION": "apac", "DEP_REGION": "emea"},
+			wantRegion: "emea",
+			wantWarn:   "warning: env 'DEP_OLD_REGION' is deprecated, use 'DEP_REGION' instead: renamed in v2\n",
+		},
+		{
+			name:       "flag beats forwarded env",
+			args:       []string{"--region", "us"},
+			env:        map[string]string{"DEP_OLD_REGION": "apac"},
+			wantRegion: "us",
+			wantWarn:   "warning: env 'DEP_OLD_REGION' is deprecated, use 'DEP_REGION' instead: renamed in v2\n",
+		},
+		{
+			name:     "no replacement",
+			args:     []string{"--legacy"},
+			wantWarn: "warning: flag '--legacy' is deprecated: no longer needed\n",
+		},
+		{
+			name:       "unused deprecated flag is silent",
+			args:       []string{"--region", "us"},
+			wantRegion: "us",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &depConfig{}
+			app, _ := newDepApp(got)
+			var stderr bytes.Buffer
+			app.IO(IO{Out: &bytes.Buffer{}, Err: &stderr, Env: MapEnv(tt.env)})
+
+			assertNoError(t, app.Run(append([]string{"deploy"}, tt.args...)))
+			assertEqual(t, tt.wantRegion, got.Region)
+			assertEqual(t, tt.wantWarn, stderr.String())
+		})
+	}
+}
+
+func Test_Deprecated_NoWarningForHelpValues(t *testing.T) {
+	got := &depConfig{}
+	app, _ := newDepApp(got)
+	var stderr bytes.Buffer
+	app.IO(IO{Out: &bytes.Buffer{}, Err: &stderr, Env: MapEnv(map[string]string{"DEP_OLD_REGION": "apac"})})
+
+	assertErrorIs(t, app.Run([]string{"deploy", "--old-region", "eu", "--help-values"}), ErrShowingHelp)
+	assertEqual(t, "", stderr.String(), "resolving for help is not a run")
+
+	assertNoError(t, app.Run([]string{"deploy", "--old-region", "eu"}))
+	assertContains(t, stderr.String(), "warning: flag '--old-region' is deprecated", "the run still warns")
+}
+
+func Test_Deprecated_WarnsOnce(t *testing.T) {
+	got := &depConfig{}
+	app, _ := newDepApp(got)
+	var stderr bytes.Buffer
+	app.IO(IO{Out: &bytes.Buffer{}, Err: &stderr, Env: MapEnv(nil)})
+
+	assertNoError(t, app.Run([]string{"deploy", "--old-region", "eu"}))
+	assertNoError(t, app.Run([]string{"deploy", "--old-region", "us"}))
+	assertEqual(t, 1, strings.Count(stderr.String(), "warning:"), "one warning across runs")
+	assertEqual(t, "us", got.Region)
+}
+
+func Test_Deprecated_Command(t *testing.T) {
+	app := NewApp(Config{Name: "app"}, GlobalFlags{})
+	app.Add("help", NewMockCommand(func() error { return nil }))
+	ran := false
+	db := NewMockCommand(func() error { return nil })
+	old := NewMockCommand(func() error {
+		ran = true
+		return nil
+	})
+	old.Deprecate("will be removed in v2", "db up")
+	app.Add("db", db).Add("migrate", old)
+	var stderr bytes.Buffer
+	app.IO(IO{Out: &bytes.Buffer{}, Err: &stderr, Env: MapEnv(nil)})
+
+	assertNoError(t, app.Run([]string{"db", "migrate"}))
+	assertEqual(t, true, ran, "a deprecated command still runs")
+	assertEqual(t, "warning: command 'db migrate' is deprecated, use 'db up' instead: will be removed in v2\n", stderr.String())
+}
+
+func Test_Deprecated_CompletionSkips(t *testing.T) {
+	app := newTestApp(Config{Name: "app"}, GlobalFlags{})
+	old := NewMockCommand(func() error { return nil })
+	old.Deprecate("gone", "")
+	app.Add("deploy", &depCommand{BaseCommand: NewBaseCommand[depConfig](), got: &depConfig{}})
+	app.Add("destroy", old)
+
+	out := completeOutput(app, "de")
+	assertContains(t, out, "deploy")
+	if strings.Contains(out, "destroy") {
+		t.Fatalf("expected deprecated command to be left out of completion, got:\n%s", out)
+	}
+
+	out = completeOutput(app, "deploy", "--")
+	assertContains(t, out, "--region")
+	if strings.Contains(out, "--old-region") || strings.Contains(out, "--legacy") {
+		t.Fatalf("expected deprecated flags to be left out of completion, got:\n%s", out)
+	}
+}
@@ -15,6 +15,11 @@ var ErrDotenvNotFound = errors.New("dotenv file not found")
 // With no arguments, it loads ".env" from the current working directory.
 // Silently skips files that do not exist.
 func LoadDotEnv(paths ...string) error {
+	return loadDotEnv(OSEnv(), paths)
+}
+
+// loadDotEnv is LoadDotEnv into env (the App's Environment, see App.DotEnv).
+func loadDotEnv(env Environment, paths []string) error {
 	if len(paths) == 0 {
 		paths = []string{".env"}
 	}
@@ -29,8 +34,10 @@ func LoadDotEnv(paths ...string) error {
 		}
 
 		for key, value := range values {
-			if _, exists := os.LookupEnv(key); !exists {
-				os.Setenv(key, value)
+			if _, exists := env.LookupEnv(key); !exists {
+				if err := env.Setenv(key, value); err != nil {
+					return fmt.Errorf("failed to set %q from env file %q: %w", key, path, err)
+				}
 			}
 		}
 	}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/toaweme/structs"
+)
+
+// envOptOut is the env tag value that keeps a field from being bound to a derived env var (see Config.EnvPrefix).
+const envOptOut = "-"
+
+// EnvPrefixer is implemented by commands that know the env prefix of the app they were added to.
+// BaseCommand implements it; the App binds the prefix when the command is added.
+type EnvPrefixer interface {
+	EnvPrefix() string
+}
+
+// envPrefixBinder is satisfied by every command embedding BaseCommand, so the App can hand it Config.EnvPrefix.
+type envPrefixBinder interface {
+	bindEnvPrefix(prefix string)
+}
+
+// EnvName returns the env var a flag is bound to under prefix: its name upper-cased, with dots and dashes
+// turned into underscores, after the prefix. EnvName("MYAPP", "database.host") is "MYAPP_DATABASE_HOST".
+func EnvName(prefix, arg string) string {
+	name := strings.NewReplacer(".", "_", "-", "_").Replace(arg)
+
+	return strings.ToUpper(strings.TrimSuffix(prefix, "_") + "_" + name)
+}
+
+// Fields returns the fields of cmd's options with each field's env tag as the App binds it: explicit tags
+// as written, derived names (see Config.EnvPrefix) for fields without one, and none for fields opted out
+// with env:"-". Help renderers read fields through it so they list the same names the merge reads.
+func Fields(cmd Command[any]) ([]structs.Field, error) {
+	fields, err := structs.GetStructFields(cmd.Options(), nil, structs.DefaultEncodingTags)
+	if err != nil {
+		return nil, err
+	}
+	applyEnvNames(fields, envPrefixOf(cmd))
+
+	return fields, nil
+}
+
+// envPrefixOf returns the env prefix bound to cmd, or "" when it has none.
+func envPrefixOf(cmd Command[any]) string {
+	if p, ok := cmd.(EnvPrefixer); ok {
+		return p.EnvPrefix()
+	}
+
+	return ""
+}
+
+// bindEnvPrefix hands prefix to cmd and every subcommand beneath it.
+func bindEnvPrefix(cmd Command[any], prefix string) {
+	if prefix == "" {
+		return
+	}
+	if binder, ok := cmd.(envPrefixBinder); ok {
+		binder.bindEnvPrefix(prefix)
+	}
+	for _, sub := range cmd.Commands() {
+		bindEnvPrefix(sub, prefix)
+	}
+}
+
+// derivedEnvName returns the env var field is bound to through prefix: "" when there is no prefix,
+// the field has (or opts out with) an env tag, or it is a positional argument.
+func derivedEnvName(field structs.Field, prefix string) string {
+	arg := fieldTag(field, tagArg)
+	if prefix == "" || arg == "" || isPositional(arg) || field.Tags["env"] != "" {
+		return ""
+	}
+	if field.FQN != nil && field.FQN.Tags["env"] != "" {
+		return ""
+	}
+
+	return EnvName(prefix, arg)
+}
+
+// applyEnvNames rewrites the env tags of fields, nested ones included, to the names the App binds:
+// a derived name where there is none and nothing where the field opts out.
+func applyEnvNames(fields []structs.Field, prefix string) {
+	walkFields(fields, "", func(field structs.Field, _ string) {
+		if field.Tags["env"] == envOptOut {
+			delete(field.Tags, "env")
+			if field.FQN != nil {
+				delete(field.FQN.Tags, "env")
+			}
+			return
+		}
+		name := derivedEnvName(field, prefix)
+		if name == "" || field.Tags == nil {
+			return
+		}
+		field.Tags["env"] = name
+		if field.FQN != nil && field.FQN.Tags != nil {
+			field.FQN.Tags["env"] = name
+		}
+	})
+}
+
+// derivedEnv folds the env vars bound through Config.EnvPrefix into values under each field's flag name:
+// structs only matches an env var by an explicit env tag. Env beats the resolver layers either way.
+func (c *app) derivedEnv(fields []structs.Field, values map[string]any) {
+	env := c.stdio().Env
+	walkFields(fields, "", func(field structs.Field, _ string) {
+		name := derivedEnvName(field, c.config.EnvPrefix)
+		if name == "" {
+			return
+		}
+		if value, ok := env.LookupEnv(name); ok {
+			values[fieldTag(field, tagArg)] = value
+		}
+	})
+}
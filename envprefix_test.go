@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/toaweme/structs"
+)
+
+type prefixDB struct {
+	Host string `arg:"host"`
+	Port int    `arg:"port" env:"DB_PORT"`
+}
+
+type prefixConfig struct {
+	Region   string   `arg:"region"`
+	DryRun   bool     `arg:"dry-run"`
+	Token    string   `arg:"token" env:"-"`
+	Mode     string   `arg:"mode" env:"DEPLOY_MODE"`
+	Target   string   `arg:"0"`
+	Database prefixDB `arg:"database"`
+	Name     string   `arg:"name" rules:"required"`
+}
+
+type prefixCommand struct {
+	BaseCommand[prefixConfig]
+	got prefixConfig
+}
+
+func (c *prefixCommand) Help() string { return "deploy" }
+func (c *prefixCommand) Run(_ GlobalFlags, _ Unknowns) error {
+	c.got = *c.Inputs
+	return nil
+}
+
+func newPrefixApp(env map[string]string) (App, *prefixCommand) {
+	cmd := &prefixCommand{BaseCommand: NewBaseCommand[prefixConfig]()}
+	app := NewApp(Config{Name: "app", EnvPrefix: "MYAPP"}, GlobalFlags{}).IO(IO{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Env: MapEnv(env)})
+	app.Add("help", &recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+	app.Add("deploy", cmd)
+	return app, cmd
+}
+
+func Test_EnvName(t *testing.T) {
+	assertEqual(t, "MYAPP_DATABASE_HOST", EnvName("MYAPP", "database.host"))
+	assertEqual(t, "MYAPP_DRY_RUN", EnvName("MYAPP", "dry-run"))
+	assertEqual(t, "MYAPP_REGION", EnvName("MYAPP_", "region"), "a trailing underscore is not doubled")
+	assertEqual(t, "MYAPP_REGION", EnvName("myapp", "region"))
+}
+
+func Test_EnvPrefix_Merge(t *testing.T) {
+	t.Parallel()
+
+	app, cmd := newPrefixApp(map[string]string{
+		"MYAPP_REGION":        "eu",
+		"MYAPP_DRY_RUN":       "true",
+		"MYAPP_TOKEN":         "ignored",
+		"MYAPP_MODE":          "ignored",
+		"DEPLOY_MODE":         "fast",
+		"MYAPP_DATABASE_HOST": "db.internal",
+		"DB_PORT":             "6543",
+		"MYAPP_NAME":          "web",
+	})
+	assertNoError(t, app.Run([]string{"deploy"}))
+	assertEqual(t, "eu", cmd.got.Region, "a flag without an env tag reads PREFIX_FLAG")
+	assertEqual(t, true, cmd.got.DryRun, "dashes become underscores")
+	assertEqual(t, "", cmd.got.Token, `env:"-" opts out`)
+	assertEqual(t, "fast", cmd.got.Mode, "an explicit env tag is used as written")
+	assertEqual(t, "db.internal", cmd.got.Database.Host, "a nested flag reads PREFIX_PARENT_FLAG")
+	assertEqual(t, 6543, cmd.got.Database.Port)
+	assertEqual(t, "web", cmd.got.Name, "a derived env var satisfies required")
+
+	assertNoError(t, app.Run([]string{"deploy", "--region", "us"}))
+	assertEqual(t, "us", cmd.got.Region, "a flag still beats the derived env var")
+}
+
+func Test_EnvPrefix_Fields(t *testing.T) {
+	t.Parallel()
+
+	_, cmd := newPrefixApp(nil)
+	sub := &prefixCommand{BaseCommand: NewBaseCommand[prefixConfig]()}
+	cmd.Add("later", sub)
+
+	fields, err := Fields(sub)
+	assertNoError(t, err)
+	envs := map[string]string{}
+	walkFields(fields, "", func(field structs.Field, path string) { envs[path] = field.Tags["env"] })
+	assertEqual(t, "MYAPP_REGION", envs["Region"], "a subcommand added later inherits the prefix")
+	assertEqual(t, "", envs["Token"], "an opted-out field has no env name")
+	assertEqual(t, "DEPLOY_MODE", envs["Mode"])
+	assertEqual(t, "", envs["Target"], "positionals have no env name")
+	assertEqual(t, "MYAPP_DATABASE_HOST", envs["Database.Host"])
+}
+
+func Test_EnvPrefix_ValidationError(t *testing.T) {
+	t.Parallel()
+
+	app, _ := newPrefixApp(nil)
+	var invalid *ValidationError
+	if err := app.Run([]string{"deploy"}); !errors.As(err, &invalid) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+	assertEqual(t, "MYAPP_NAME", invalid.Fields[0].Env, "validation errors name the derived env var")
+}
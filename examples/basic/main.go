@@ -30,9 +30,5 @@ func main() {
 	app.Help(help.NewHelpCommand(app.Config, app.Commands, app.OutputFormats, app.DefaultCommand))
 	app.Add("info", &InfoCommand{BaseCommand: cli.NewBaseCommand[InfoConfig]()})
 
-	// IsRealError filters out the clean-exit sentinels (help/version already handled)
-	if err := app.Run(os.Args[1:]); cli.IsRealError(err) {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	app.Main()
 }
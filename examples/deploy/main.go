@@ -35,8 +35,5 @@ func main() {
 	parent.Add("staging", &DeployCommand{BaseCommand: cli.NewBaseCommand[DeployConfig]()})
 	parent.Add("production", &DeployCommand{BaseCommand: cli.NewBaseCommand[DeployConfig]()})
 
-	if err := app.Run(os.Args[1:]); cli.IsRealError(err) {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	app.Main()
 }
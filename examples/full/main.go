@@ -10,6 +10,7 @@ import (
 	"github.com/toaweme/cli/commands/completion"
 	"github.com/toaweme/cli/commands/gendocs"
 	"github.com/toaweme/cli/commands/help"
+	"github.com/toaweme/cli/commands/shell"
 	"github.com/toaweme/cli/config"
 )
 
@@ -59,6 +60,8 @@ func main() {
 	app.Add("completion", completion.NewCompletionCommand(appName))
 	// generates reference docs for this app in every help format: full gendocs
 	app.Add("gendocs", gendocs.NewGenDocsCommand(app.Config, app.Commands, app.OutputFormats))
+	// runs commands interactively, one per line, with history and tab completion: full shell
+	app.Add("shell", shell.NewShellCommand(app))
 
 	buildCmd := &BuildCommand{BaseCommand: cli.NewBaseCommand[BuildConfig]()}
 	app.Add("build", buildCmd)
@@ -80,8 +83,5 @@ func main() {
 	db.Add("seed", &DBSeedCommand{BaseCommand: cli.NewBaseCommand[DBSeedConfig]()})
 	db.Add("reset", &DBResetCommand{BaseCommand: cli.NewBaseCommand[DBResetConfig]()})
 
-	if err := app.Run(os.Args[1:]); cli.IsRealError(err) {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	app.Main()
 }
@@ -90,10 +90,5 @@ func main() {
 	// App.Commands returns the registered top-level commands, in registration order.
 	fmt.Fprintf(os.Stderr, "registered %d top-level commands\n", len(app.Commands()))
 
-	// App.Run parses os.Args[1:] and dispatches.
-	// Help and version requests come back as sentinel errors, which are clean exits rather than failures.
-	if err := app.Run(os.Args[1:]); cli.IsRealError(err) {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	app.Main()
 }
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/toaweme/cli"
@@ -24,6 +22,7 @@ type StartConfig struct {
 }
 
 // StartCommand starts an HTTP server with graceful shutdown on SIGINT/SIGTERM.
+// The signal handling is the framework's: Context() is cancelled on the first Ctrl-C.
 type StartCommand struct {
 	cli.BaseCommand[StartConfig]
 	store *config.FileStore
@@ -55,9 +54,8 @@ func (c *StartCommand) Run(_ cli.GlobalFlags, _ cli.Unknowns) error {
 		return fmt.Errorf("failed to save server config: %w", err)
 	}
 
-	// graceful shutdown on SIGINT/SIGTERM
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+	// graceful shutdown: the app cancels the run context on SIGINT/SIGTERM
+	ctx := c.Context()
 
 	go func() {
 		fmt.Printf("listening on %s\n", addr)
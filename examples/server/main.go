@@ -5,6 +5,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/toaweme/cli"
 	"github.com/toaweme/cli/commands/completion"
@@ -29,10 +30,11 @@ func main() {
 	// config store for the last-run server state at ~/.server/last.json
 	store := config.NewFileStore(config.HomePath(appName), "last", true)
 
+	// a second Ctrl-C, or 30s without the server stopping, force-exits
 	app := cli.NewApp(
 		cli.Config{Name: appName, Version: appVersion},
 		cli.GlobalFlags{Cwd: cwd},
-	)
+	).GracePeriod(30 * time.Second)
 
 	app.Help(help.NewHelpCommand(app.Config, app.Commands, app.OutputFormats, app.DefaultCommand))
 	app.Add("completion", completion.NewCompletionCommand(appName))
@@ -47,8 +49,5 @@ func main() {
 
 	app.Add("status", &StatusCommand{BaseCommand: cli.NewBaseCommand[StatusConfig]()})
 
-	if err := app.Run(os.Args[1:]); cli.IsRealError(err) {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
-	}
+	app.Main()
 }
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Exit codes Main maps errors to (see ExitCode). Commands needing others return an ExitCoder.
+const (
+	// ExitOK is a successful run, including a handled --help or --version request.
+	ExitOK = 0
+	// ExitError is a command failure.
+	ExitError = 1
+	// ExitUsage is a usage mistake: an unknown command or inputs failing validation.
+	ExitUsage = 2
+)
+
+// ExitCoder is implemented by errors that carry their own process exit code. Main exits with the
+// code of the first ExitCoder in the error chain, so a command can return one from Run (wrapped or not).
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+// exitError is the ExitCoder WithExitCode returns.
+type exitError struct {
+	err  error
+	code int
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+func (e *exitError) ExitCode() int { return e.code }
+
+// WithExitCode wraps err so Main exits with code when it surfaces. A nil err stays nil.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+
+	return &exitError{err: err, code: code}
+}
+
+// ExitCode maps the error Run returned to the process exit code Main uses:
+//
+//   - nil, a handled --help/--version request, or a broken stdout pipe: ExitOK
+//   - an ExitCoder anywhere in the chain: its own code
+//   - an unknown command, an invalid flag, or a validation failure: ExitUsage
+//   - anything else: ExitError
+//
+// A bare invocation that shows help is a request, not a mistake, so it exits ExitOK;
+// a mistyped command shows help too, but exits ExitUsage.
+func ExitCode(err error) int {
+	if err == nil || errors.Is(err, syscall.EPIPE) {
+		return ExitOK
+	}
+
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	var unknown *UnknownCommandError
+	if errors.As(err, &unknown) || errors.Is(err, ErrValidationFailed) || errors.Is(err, ErrInvalidFlag) {
+		return ExitUsage
+	}
+	if !IsRealError(err) {
+		return ExitOK
+	}
+	if errors.Is(err, ErrCommandNotFound) {
+		return ExitUsage
+	}
+
+	return ExitError
+}
+
+// Main runs the app on os.Args[1:] and exits the process: it prints a genuine error to stderr and exits
+// with ExitCode(err). The error is rendered by the help command when it is an ErrorDisplayer, so it follows
+// --help-format (JSON for the json formats), and as "error: <message>" otherwise. Errors already shown with
+// the help output (an unknown command) and a broken stdout pipe (e.g. `app list | head`) are not printed
+// again. It replaces the usual
+//
+//	if err := app.Run(os.Args[1:]); cli.IsRealError(err) { ... os.Exit(1) }
+func (c *app) Main() {
+	err := c.Run(os.Args[1:])
+	if IsRealError(err) && !errors.Is(err, syscall.EPIPE) {
+		c.displayError(err)
+	}
+
+	c.exitProcess(ExitCode(err))
+}
+
+// displayError writes err to stderr through the help command when it is an ErrorDisplayer, in the
+// --help-format of the run, and as a plain "error: <message>" line otherwise.
+func (c *app) displayError(err error) {
+	w := c.stdio().Err
+	for _, cmd := range c.commands {
+		if displayer, ok := cmd.(ErrorDisplayer); ok && cmd.Name("") == helpCommand {
+			displayer.DisplayError(w, err, c.globalFlags.HelpFormat)
+			return
+		}
+	}
+
+	fmt.Fprintf(w, "error: %v\n", err)
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type codedError struct{ code int }
+
+func (e *codedError) Error() string { return "coded" }
+func (e *codedError) ExitCode() int { return e.code }
+
+func Test_ExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: ExitOK},
+		{name: "help", err: ErrShowingHelp, want: ExitOK},
+		{name: "version", err: ErrShowingVersion, want: ExitOK},
+		{name: "bare invocation shows help", err: fmt.Errorf("%w: %w", ErrCommandNotFound, ErrShowingHelp), want: ExitOK},
+		{name: "unknown command", err: fmt.Errorf("%w: %w", &UnknownCommandError{Name: "x"}, ErrShowingHelp), want: ExitUsage},
+		{name: "command not found", err: ErrCommandNotFound, want: ExitUsage},
+		{name: "validation", err: fmt.Errorf("failed to validate command %q: %w", "x", ErrValidationFailed), want: ExitUsage},
+		{name: "exit coder", err: fmt.Errorf("failed to run command %q: %w", "x", &codedError{code: 42}), want: 42},
+		{name: "with exit code", err: WithExitCode(ErrValidationFailed, 3), want: 3},
+		{name: "broken pipe", err: fmt.Errorf("write /dev/stdout: %w", syscall.EPIPE), want: ExitOK},
+		{name: "other", err: errors.New("boom"), want: ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
+
+func Test_WithExitCode_Nil(t *testing.T) {
+	assertNil(t, WithExitCode(nil, 3))
+}
+
+func Test_App_Main(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		runErr   error
+		wantCode int
+		wantErr  string
+	}{
+		{name: "success", args: []string{"run"}, wantCode: ExitOK},
+		{name: "failure", args: []string{"run"}, runErr: errors.New("boom"), wantCode: ExitError, wantErr: "error: failed to run command \"run\": boom\n"},
+		{name: "custom code", args: []string{"run"}, runErr: WithExitCode(errors.New("busy"), 75), wantCode: 75, wantErr: "error: failed to run command \"run\": busy\n"},
+		{name: "broken pipe is quiet", args: []string{"run"}, runErr: fmt.Errorf("write: %w", syscall.EPIPE), wantCode: ExitOK},
+		{name: "version", args: []string{"--version"}, wantCode: ExitOK},
+		{name: "unknown command", args: []string{"rnu"}, wantCode: ExitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := -1
+			origArgs := os.Args
+			os.Args = append([]string{"app"}, tt.args...)
+			t.Cleanup(func() { os.Args = origArgs })
+
+			a := NewApp(Config{Name: "app", Version: "1.0.0"}, GlobalFlags{}).(*app)
+			a.exit = func(c int) { code = c }
+			var stderr bytes.Buffer
+			a.IO(IO{Out: &bytes.Buffer{}, Err: &stderr})
+			a.Help(&recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+			a.Add("run", NewMockCommand(func() error { return tt.runErr }))
+
+			a.Main()
+			assertEqual(t, tt.wantCode, code)
+			assertEqual(t, tt.wantErr, stderr.String())
+		})
+	}
+}
@@ -38,7 +38,7 @@ func DisplayHelpAgent(w io.Writer, opts AgentOptions) {
 	commands := opts.Commands
 	format := resolveFormat(opts.Format)
 
-	if format == "pretty" && !isTTY() {
+	if format == "pretty" && !isTTY(w) {
 		format = "plain"
 	}
 
@@ -61,8 +61,8 @@ func DisplayHelpAgent(w io.Writer, opts AgentOptions) {
 func buildAgentOutput(appName string, commands []cli.Command[any], format string, extraFormats []string, showValues bool, globalValues *cli.GlobalFlags, defaultCommand string) string {
 	var b strings.Builder
 
-	for _, cmd := range commands {
-		writeAgentCommand(&b, cmd, "", appName, format, showValues, defaultCommand)
+	for _, cmd := range listedCommands(commands) {
+		writeAgentCommand(&b, cmd, "", appName, format, showValues, defaultCommand, nil)
 	}
 
 	if format == "md" || format == "pretty" {
@@ -75,7 +75,9 @@ func buildAgentOutput(appName string, commands []cli.Command[any], format string
 	return b.String()
 }
 
-func writeAgentCommand(b *strings.Builder, cmd cli.Command[any], prefix, appName, format string, showValues bool, defaultCommand string) {
+// writeAgentCommand writes cmd's section and recurses into its subcommands. inherited holds the flags
+// cmd inherits from its ancestors (see cli.Persistent), listed in a table of their own.
+func writeAgentCommand(b *strings.Builder, cmd cli.Command[any], prefix, appName, format string, showValues bool, defaultCommand string, inherited []structs.Field) {
 	name := prefix + cmd.Name("")
 	help := cmd.Help()
 
@@ -91,6 +93,12 @@ func writeAgentCommand(b *strings.Builder, cmd cli.Command[any], prefix, appName
 	if help != "" {
 		fmt.Fprintf(b, "  %s\n", firstLine(help))
 	}
+	if aliases := cli.CommandAliases(cmd); len(aliases) > 0 {
+		fmt.Fprintf(b, "  Aliases: %s\n", strings.Join(aliases, ", "))
+	}
+	if acceptsPassthrough(cmd) {
+		fmt.Fprintf(b, "  Usage: %s %s %s\n", appName, name, passthroughUsage)
+	}
 	if desc := commandDescription(cmd); desc != "" {
 		if format == "md" || format == "pretty" {
 			b.WriteString("\n" + desc + "\n")
@@ -105,11 +113,21 @@ func writeAgentCommand(b *strings.Builder, cmd cli.Command[any], prefix, appName
 		}
 	}
 
-	rows := extractFlagRows(cmd.Options(), showValues)
+	rows := flagRows(commandFields(cmd), nil, showValues)
 	if len(rows) > 0 {
 		writeAgentFlagRows(b, rows, "  ", format)
 	}
 
+	var inheritedRows []flagRow
+	inheritedNotes := relationNotes(inherited)
+	for _, field := range currentFields(inherited) {
+		inheritedRows = appendFlagRows(inheritedRows, field, nil, inheritedNotes, showValues)
+	}
+	if len(inheritedRows) > 0 {
+		b.WriteString("\n  Inherited flags:\n")
+		writeAgentFlagRows(b, inheritedRows, "  ", format)
+	}
+
 	for _, line := range providerDocLines(cmd, "  ") {
 		b.WriteString(line + "\n")
 	}
@@ -134,8 +152,8 @@ func writeAgentCommand(b *strings.Builder, cmd cli.Command[any], prefix, appName
 		}
 	}
 
-	for _, sub := range cmd.Commands() {
-		writeAgentCommand(b, sub, name+" ", appName, format, showValues, defaultCommand)
+	for _, sub := range listedCommands(cmd.Commands()) {
+		writeAgentCommand(b, sub, name+" ", appName, format, showValues, defaultCommand, inheritedFields(cmd, inherited))
 	}
 }
 
@@ -154,12 +172,8 @@ type flagRow struct {
 	Value string
 }
 
-func extractFlagRows(options any, showValues bool) []flagRow {
-	return extractFlagRowsWithFormats(options, nil, showValues)
-}
-
-// extractFlagRowsWithFormats is extractFlagRows with extra --help-format values to append
-// to the format flag's allowed-values hint, used when rendering global options.
+// extractFlagRowsWithFormats returns the flag rows of an options struct, with extra --help-format values
+// to append to the format flag's allowed-values hint, used when rendering global options.
 func extractFlagRowsWithFormats(options any, extraFormats []string, showValues bool) []flagRow {
 	if options == nil {
 		return nil
@@ -170,9 +184,15 @@ func extractFlagRowsWithFormats(options any, extraFormats []string, showValues b
 		return nil
 	}
 
+	return flagRows(fields, extraFormats, showValues)
+}
+
+// flagRows returns the rows for the current (non-deprecated) fields among fields.
+func flagRows(fields []structs.Field, extraFormats []string, showValues bool) []flagRow {
 	var rows []flagRow
-	for _, field := range fields {
-		rows = appendFlagRows(rows, field, extraFormats, showValues)
+	notes := relationNotes(fields)
+	for _, field := range currentFields(fields) {
+		rows = appendFlagRows(rows, field, extraFormats, notes, showValues)
 	}
 
 	return rows
@@ -181,18 +201,19 @@ func extractFlagRowsWithFormats(options any, extraFormats []string, showValues b
 // appendFlagRows adds a row for field when it carries a flag tag, then recurses into nested struct sub-fields.
 // Sub-fields are addressed by their dotted FQN tag (e.g. "database.host") and may carry their
 // own oneof rule, so they render in the flag table the same way top-level flags do.
-// extraFormats rides along on the --help-format field's allowed-values hint (see formatHintExtras).
-func appendFlagRows(rows []flagRow, field structs.Field, extraFormats []string, showValues bool) []flagRow {
+// extraFormats rides along on the --help-format field's allowed-values hint (see formatHintExtras), and
+// notes (see relationNotes) on the help of the flags relation rules name.
+func appendFlagRows(rows []flagRow, field structs.Field, extraFormats []string, notes map[string]string, showValues bool) []flagRow {
 	if (field.Tags["arg"] != "" || field.Tags["short"] != "") && !isPositionalArg(field.Tags["arg"]) {
 		value := ""
 		if showValues {
 			value = valueText(field)
 		}
 		rows = append(rows, flagRow{
-			Flag:     flagArg(field),
+			Flag:     displayArg(field, flagArg(field)),
 			Short:    field.Tags["short"],
 			Type:     displayType(field),
-			Help:     withAllowedValues(field.Tags["help"], field, formatHintExtras(field, extraFormats)),
+			Help:     withRelationNote(withAllowedValues(field.Tags["help"], field, formatHintExtras(field, extraFormats)), notes[flagArg(field)]),
 			Env:      flagEnv(field),
 			Required: hasRule(field, "required"),
 			Default:  field.Default,
@@ -201,7 +222,7 @@ func appendFlagRows(rows []flagRow, field structs.Field, extraFormats []string,
 	}
 
 	for _, sub := range field.Fields {
-		rows = appendFlagRows(rows, sub, extraFormats, showValues)
+		rows = appendFlagRows(rows, sub, extraFormats, notes, showValues)
 	}
 
 	return rows
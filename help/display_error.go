@@ -0,0 +1,63 @@
+package help
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/toaweme/cli"
+)
+
+// ErrorInfo is the serialized representation of a failure that diverted a run to help,
+// written alongside JSON help so machine consumers get the suggestions without parsing prose.
+type ErrorInfo struct {
+	Error string `json:"error" yaml:"error" toml:"error"`
+	// Command is the unknown command name, when the failure is an unknown command.
+	Command     string   `json:"command,omitempty" yaml:"command,omitempty" toml:"command,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty" yaml:"suggestions,omitempty" toml:"suggestions,omitempty"`
+	// Fields lists each failed rule, when the failure is a *cli.ValidationError.
+	Fields []cli.FieldError `json:"fields,omitempty" yaml:"fields,omitempty" toml:"fields,omitempty"`
+}
+
+// NewErrorInfo builds the ErrorInfo for err, lifting the name and suggestions out of an *cli.UnknownCommandError
+// and the failed fields out of a *cli.ValidationError.
+func NewErrorInfo(err error) ErrorInfo {
+	info := ErrorInfo{Error: err.Error()}
+	var unknown *cli.UnknownCommandError
+	if errors.As(err, &unknown) {
+		info.Command = unknown.Name
+		info.Suggestions = unknown.Suggestions
+	}
+	var invalid *cli.ValidationError
+	if errors.As(err, &invalid) {
+		info.Fields = invalid.Fields
+	}
+
+	return info
+}
+
+// DisplayError writes err to w: as an indented ErrorInfo object for the json and jsonschema formats,
+// and as a single "error: ..." line otherwise, followed by one indented line per failed field
+// for a *cli.ValidationError.
+func DisplayError(w io.Writer, err error, format string) {
+	if format != "json" && format != "jsonschema" {
+		var invalid *cli.ValidationError
+		if !errors.As(err, &invalid) {
+			fmt.Fprintf(w, "error: %v\n", err)
+			return
+		}
+		fmt.Fprintf(w, "error: %v\n", cli.ErrValidationFailed)
+		for _, field := range invalid.Fields {
+			fmt.Fprintf(w, "  %v\n", field)
+		}
+		return
+	}
+
+	data, marshalErr := json.MarshalIndent(NewErrorInfo(err), "", "  ")
+	if marshalErr != nil {
+		fmt.Fprintf(w, "error: %v\n", err)
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
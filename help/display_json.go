@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -17,14 +18,22 @@ import (
 // ArgDocs is keyed by the positional index as a string ("0", "1") rather than an int
 // so it round-trips through codecs like toml, whose table keys must be strings.
 type CommandInfo struct {
-	Name        string              `json:"name" yaml:"name" toml:"name"`
-	Help        string              `json:"help" yaml:"help" toml:"help"`
-	Description string              `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
-	Flags       []FlagInfo          `json:"flags,omitempty" yaml:"flags,omitempty" toml:"flags,omitempty"`
-	Examples    [][]string          `json:"examples,omitempty" yaml:"examples,omitempty" toml:"examples,omitempty"`
-	ArgDocs     map[string][]string `json:"argDescriptions,omitempty" yaml:"argDescriptions,omitempty" toml:"argDescriptions,omitempty"`
-	FlagDocs    map[string][]string `json:"flagDescriptions,omitempty" yaml:"flagDescriptions,omitempty" toml:"flagDescriptions,omitempty"`
-	SubCommands []CommandInfo       `json:"subcommands,omitempty" yaml:"subcommands,omitempty" toml:"subcommands,omitempty"`
+	Name        string           `json:"name" yaml:"name" toml:"name"`
+	Aliases     []string         `json:"aliases,omitempty" yaml:"aliases,omitempty" toml:"aliases,omitempty"`
+	Deprecated  *DeprecationInfo `json:"deprecated,omitempty" yaml:"deprecated,omitempty" toml:"deprecated,omitempty"`
+	Help        string           `json:"help" yaml:"help" toml:"help"`
+	Description string           `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Flags       []FlagInfo       `json:"flags,omitempty" yaml:"flags,omitempty" toml:"flags,omitempty"`
+	// InheritedFlags are the persistent flags the command accepts from its ancestors (see cli.Persistent).
+	InheritedFlags []FlagInfo          `json:"inheritedFlags,omitempty" yaml:"inheritedFlags,omitempty" toml:"inheritedFlags,omitempty"`
+	Examples       [][]string          `json:"examples,omitempty" yaml:"examples,omitempty" toml:"examples,omitempty"`
+	ArgDocs        map[string][]string `json:"argDescriptions,omitempty" yaml:"argDescriptions,omitempty" toml:"argDescriptions,omitempty"`
+	FlagDocs       map[string][]string `json:"flagDescriptions,omitempty" yaml:"flagDescriptions,omitempty" toml:"flagDescriptions,omitempty"`
+	SubCommands    []CommandInfo       `json:"subcommands,omitempty" yaml:"subcommands,omitempty" toml:"subcommands,omitempty"`
+	// Passthrough is set for a command taking arguments after a "--" terminator.
+	Passthrough bool `json:"passthrough,omitempty" yaml:"passthrough,omitempty" toml:"passthrough,omitempty"`
+	// Relations are the rules between the command's flags (see cli.Relation).
+	Relations []RelationInfo `json:"relations,omitempty" yaml:"relations,omitempty" toml:"relations,omitempty"`
 }
 
 // FlagInfo is the serialized representation of a flag.
@@ -36,11 +45,31 @@ type FlagInfo struct {
 	Required bool   `json:"required,omitempty" yaml:"required,omitempty" toml:"required,omitempty"`
 	Default  string `json:"default,omitempty" yaml:"default,omitempty" toml:"default,omitempty"`
 	Env      string `json:"env,omitempty" yaml:"env,omitempty" toml:"env,omitempty"`
+	// Negatable is set for a bool flag advertising its "--no-<name>" form (see cli.FieldNegatable).
+	Negatable bool `json:"negatable,omitempty" yaml:"negatable,omitempty" toml:"negatable,omitempty"`
+	// Repeatable is set for a counter flag, which adds one every time it is given (see cli.FieldCounter).
+	Repeatable bool `json:"repeatable,omitempty" yaml:"repeatable,omitempty" toml:"repeatable,omitempty"`
+	// Deprecated is set for a deprecated flag, which text help leaves out.
+	Deprecated *DeprecationInfo `json:"deprecated,omitempty" yaml:"deprecated,omitempty" toml:"deprecated,omitempty"`
 	// Value is the flag's resolved value, populated only under --help-values (secret fields masked).
 	// Omitted otherwise so normal help output is unchanged.
 	Value string `json:"value,omitempty" yaml:"value,omitempty" toml:"value,omitempty"`
 }
 
+// DeprecationInfo is the serialized representation of a cli.Deprecation.
+type DeprecationInfo struct {
+	Message     string `json:"message,omitempty" yaml:"message,omitempty" toml:"message,omitempty"`
+	Replacement string `json:"replacement,omitempty" yaml:"replacement,omitempty" toml:"replacement,omitempty"`
+}
+
+// deprecationInfo converts dep for serialization, nil when it marks nothing as deprecated.
+func deprecationInfo(dep cli.Deprecation) *DeprecationInfo {
+	if !dep.IsDeprecated() {
+		return nil
+	}
+	return &DeprecationInfo{Message: dep.Message, Replacement: dep.Replacement}
+}
+
 // commandsDoc wraps the command list so codecs that cannot encode a top-level array
 // (e.g. toml) have a table to anchor to. JSON still emits a bare array via DisplayHelpJSON;
 // the keyed wrapper is only used by the generic codec path.
@@ -51,9 +80,17 @@ type commandsDoc struct {
 // CommandSchema is the JSON Schema representation of a command's options.
 type CommandSchema struct {
 	Name       string                 `json:"name"`
+	Aliases    []string               `json:"aliases,omitempty"`
+	Deprecated bool                   `json:"deprecated,omitempty"`
 	Help       string                 `json:"help"`
 	Properties map[string]SchemaField `json:"properties,omitempty"`
 	Required   []string               `json:"required,omitempty"`
+	// InheritedProperties are the persistent flags the command accepts from its ancestors (see cli.Persistent).
+	InheritedProperties map[string]SchemaField `json:"inheritedProperties,omitempty"`
+	// Dependencies, OneOf, and AllOf carry the relation rules between flags (see applyRelationSchema).
+	Dependencies map[string]SchemaCondition `json:"dependencies,omitempty"`
+	OneOf        []SchemaCondition          `json:"oneOf,omitempty"`
+	AllOf        []SchemaCondition          `json:"allOf,omitempty"`
 }
 
 // SchemaField is a single field in a JSON Schema.
@@ -62,6 +99,12 @@ type SchemaField struct {
 	Description string   `json:"description,omitempty"`
 	Default     string   `json:"default,omitempty"`
 	Enum        []string `json:"enum,omitempty"`
+	// Format is the JSON Schema format of a string parsed as a duration, time, url, or regexp.
+	Format string `json:"format,omitempty"`
+	// AdditionalProperties is the schema of a map flag's values (see cli.FieldMap), keyed by any string.
+	AdditionalProperties *SchemaField `json:"additionalProperties,omitempty"`
+	// Deprecated is the JSON Schema deprecated annotation; the description names the replacement.
+	Deprecated bool `json:"deprecated,omitempty"`
 	// Value is the field's resolved value, populated only under --help-values (secret fields masked).
 	// Omitted otherwise so the schema is unchanged in normal use.
 	Value string `json:"value,omitempty"`
@@ -131,40 +174,38 @@ func optBool(opts []bool) bool {
 
 func buildCommandInfoList(commands []cli.Command[any], showValues bool) []CommandInfo {
 	var result []CommandInfo
-	for _, cmd := range commands {
-		result = append(result, buildCommandInfo(cmd, showValues))
+	for _, cmd := range visibleCommands(commands) {
+		result = append(result, buildCommandInfo(cmd, showValues, nil))
 	}
 	return result
 }
 
-func buildCommandInfo(cmd cli.Command[any], showValues bool) CommandInfo {
+// buildCommandInfo serializes cmd and its subcommands; inherited holds the flags cmd inherits from its ancestors.
+func buildCommandInfo(cmd cli.Command[any], showValues bool, inherited []structs.Field) CommandInfo {
 	info := CommandInfo{
-		Name:        cmd.Name(""),
-		Help:        cmd.Help(),
-		Description: commandDescription(cmd),
-		Flags:       extractFlags(cmd.Options(), showValues),
-		Examples:    cmd.Examples(),
-		ArgDocs:     stringKeyedArgDocs(cmd.Args()),
-		FlagDocs:    cmd.Flags(),
+		Name:           cmd.Name(""),
+		Aliases:        cli.CommandAliases(cmd),
+		Deprecated:     deprecationInfo(cli.CommandDeprecation(cmd)),
+		Help:           cmd.Help(),
+		Description:    commandDescription(cmd),
+		Flags:          flagInfos(commandFields(cmd), showValues),
+		InheritedFlags: flagInfos(inherited, showValues),
+		Examples:       cmd.Examples(),
+		ArgDocs:        stringKeyedArgDocs(cmd.Args()),
+		FlagDocs:       cmd.Flags(),
+		Passthrough:    acceptsPassthrough(cmd),
+		Relations:      relationInfos(commandFields(cmd)),
 	}
 
-	for _, sub := range cmd.Commands() {
-		info.SubCommands = append(info.SubCommands, buildCommandInfo(sub, showValues))
+	for _, sub := range visibleCommands(cmd.Commands()) {
+		info.SubCommands = append(info.SubCommands, buildCommandInfo(sub, showValues, inheritedFields(cmd, inherited)))
 	}
 
 	return info
 }
 
-func extractFlags(options any, showValues bool) []FlagInfo {
-	if options == nil {
-		return nil
-	}
-
-	fields, err := structs.GetStructFields(options, nil, structs.DefaultEncodingTags)
-	if err != nil {
-		return nil
-	}
-
+// flagInfos serializes the flag fields among fields, skipping positional args.
+func flagInfos(fields []structs.Field, showValues bool) []FlagInfo {
 	var flags []FlagInfo
 	for _, field := range fields {
 		if field.Tags["arg"] == "" && field.Tags["short"] == "" {
@@ -175,12 +216,15 @@ func extractFlags(options any, showValues bool) []FlagInfo {
 		}
 
 		fi := FlagInfo{
-			Name:    field.Tags["arg"],
-			Short:   field.Tags["short"],
-			Help:    field.Tags["help"],
-			Type:    field.Type,
-			Default: field.Tags["default"],
-			Env:     field.Tags["env"],
+			Name:       field.Tags["arg"],
+			Short:      field.Tags["short"],
+			Help:       field.Tags["help"],
+			Type:       jsonType(field),
+			Default:    field.Tags["default"],
+			Env:        field.Tags["env"],
+			Deprecated: deprecationInfo(cli.FieldDeprecation(field)),
+			Negatable:  cli.FieldNegatable(field),
+			Repeatable: cli.FieldCounter(field),
 		}
 		if hasRule(field, "required") {
 			fi.Required = true
@@ -196,10 +240,12 @@ func extractFlags(options any, showValues bool) []FlagInfo {
 
 func buildSchemaList(commands []cli.Command[any], showValues bool) []CommandSchema {
 	var result []CommandSchema
-	for _, cmd := range commands {
+	for _, cmd := range visibleCommands(commands) {
 		result = append(result, buildSchema(cmd, showValues))
-		for _, sub := range cmd.Commands() {
+		inherited := inheritedFields(cmd, nil)
+		for _, sub := range visibleCommands(cmd.Commands()) {
 			schema := buildSchema(sub, showValues)
+			schema.InheritedProperties = schemaFields(inherited, showValues)
 			schema.Name = cmd.Name("") + " " + schema.Name
 			result = append(result, schema)
 		}
@@ -210,43 +256,101 @@ func buildSchemaList(commands []cli.Command[any], showValues bool) []CommandSche
 func buildSchema(cmd cli.Command[any], showValues bool) CommandSchema {
 	schema := CommandSchema{
 		Name:       cmd.Name(""),
+		Aliases:    cli.CommandAliases(cmd),
+		Deprecated: cli.CommandDeprecation(cmd).IsDeprecated(),
 		Help:       cmd.Help(),
 		Properties: make(map[string]SchemaField),
 	}
 
-	options := cmd.Options()
-	if options == nil {
-		return schema
+	for _, field := range commandFields(cmd) {
+		argName := field.Tags["arg"]
+		if argName == "" || isPositionalArg(argName) {
+			continue
+		}
+
+		schema.Properties[argName] = schemaField(field, showValues)
+
+		if hasRule(field, "required") {
+			schema.Required = append(schema.Required, argName)
+		}
 	}
+	applyRelationSchema(&schema, commandFields(cmd))
 
-	fields, err := structs.GetStructFields(options, nil, structs.DefaultEncodingTags)
-	if err != nil {
-		return schema
+	return schema
+}
+
+// schemaField is the JSON Schema property for a flag field.
+func schemaField(field structs.Field, showValues bool) SchemaField {
+	sf := SchemaField{
+		Type:        schemaType(field),
+		Format:      schemaFormats[cli.FieldTypeName(field)],
+		Description: field.Tags["help"],
+		Default:     field.Tags["default"],
+		Enum:        oneOfValues(field),
+	}
+	if cli.FieldMap(field) {
+		elem := elemField(field.Value.Type().Elem())
+		sf.AdditionalProperties = &SchemaField{Type: schemaType(elem), Format: schemaFormats[cli.FieldTypeName(elem)]}
 	}
+	if dep := cli.FieldDeprecation(field); dep.IsDeprecated() {
+		sf.Deprecated = true
+		sf.Description = strings.TrimSpace(sf.Description + " (deprecated: " + deprecationText(dep) + ")")
+	}
+	if showValues {
+		sf.Value = fieldRawValue(field)
+	}
+	return sf
+}
 
+// elemField stands in for one value of a map flag whose values are of type t, so it is described as a
+// flag of that type would be: its kind for a basic type, a string of a format for a text type.
+func elemField(t reflect.Type) structs.Field {
+	return structs.Field{Type: t.Kind().String(), Value: reflect.New(t).Elem()}
+}
+
+// schemaFields maps the flag fields among fields to their JSON Schema properties, nil when there are none.
+func schemaFields(fields []structs.Field, showValues bool) map[string]SchemaField {
+	var properties map[string]SchemaField
 	for _, field := range fields {
 		argName := field.Tags["arg"]
 		if argName == "" || isPositionalArg(argName) {
 			continue
 		}
-
-		sf := SchemaField{
-			Type:        goTypeToSchemaType(field.Type),
-			Description: field.Tags["help"],
-			Default:     field.Tags["default"],
-			Enum:        oneOfValues(field),
-		}
-		if showValues {
-			sf.Value = fieldRawValue(field)
+		if properties == nil {
+			properties = make(map[string]SchemaField)
 		}
-		schema.Properties[argName] = sf
+		properties[argName] = schemaField(field, showValues)
+	}
+	return properties
+}
 
-		if hasRule(field, "required") {
-			schema.Required = append(schema.Required, argName)
-		}
+// schemaFormats are the JSON Schema formats of the built-in text types (see cli.FieldTypeName).
+var schemaFormats = map[string]string{
+	"duration": "duration",
+	"time":     "date-time",
+	"url":      "uri",
+	"regexp":   "regex",
+}
+
+// jsonType is the type JSON help reports for a flag: the friendly name of a type parsed from text
+// (see cli.FieldTypeName), the Go type otherwise.
+func jsonType(field structs.Field) string {
+	if name := cli.FieldTypeName(field); name != "" {
+		return name
 	}
+	return field.Type
+}
 
-	return schema
+// schemaType is the JSON Schema type of a flag: a string for a type parsed from text, whatever its Go kind,
+// and an object for a map.
+func schemaType(field structs.Field) string {
+	if cli.FieldMap(field) {
+		return "object"
+	}
+	if cli.FieldTypeName(field) != "" {
+		return "string"
+	}
+	return goTypeToSchemaType(field.Type)
 }
 
 func goTypeToSchemaType(goType string) string {
@@ -3,10 +3,13 @@ package help
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/toaweme/cli"
 )
@@ -531,3 +534,633 @@ func Test_DisplayHelpAgent_Formats(t *testing.T) {
 		})
 	}
 }
+
+// aliasHiddenTree is commandTree plus an aliased command and a hidden one.
+func aliasHiddenTree() []cli.Command[any] {
+	remove := &flagStub{BaseCommand: cli.NewBaseCommand[testFlags](), help: "Remove a thing"}
+	remove.Name("remove")
+	remove.Alias("rm", "del")
+	internal := &flagStub{BaseCommand: cli.NewBaseCommand[testFlags](), help: "Internal plumbing"}
+	internal.Name("internal")
+	internal.Hide()
+	return append(commandTree(), remove, internal)
+}
+
+func Test_Help_AliasesAndHidden(t *testing.T) {
+	renders := map[string]func(){
+		"text": func() { DisplayHelp(os.Stdout, "myapp", aliasHiddenTree(), nil) },
+		"agent": func() {
+			DisplayHelpAgent(os.Stdout, AgentOptions{AppName: "myapp", Format: "plain", Commands: aliasHiddenTree()})
+		},
+		"json":       func() { DisplayHelpJSON(os.Stdout, aliasHiddenTree()) },
+		"jsonschema": func() { DisplayHelpJSONSchema(os.Stdout, aliasHiddenTree()) },
+	}
+
+	for name, render := range renders {
+		t.Run(name, func(t *testing.T) {
+			out := captureStdout(t, render)
+			if strings.Contains(out, "internal") {
+				t.Fatalf("expected hidden command to be left out, got:\n%s", out)
+			}
+			if !strings.Contains(out, "rm") || !strings.Contains(out, "del") {
+				t.Fatalf("expected aliases to be listed, got:\n%s", out)
+			}
+		})
+	}
+}
+
+func Test_DisplayHelpJSON_Aliases(t *testing.T) {
+	out := captureStdout(t, func() {
+		DisplayHelpJSON(os.Stdout, aliasHiddenTree())
+	})
+
+	var infos []CommandInfo
+	if err := json.Unmarshal([]byte(out), &infos); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	for _, info := range infos {
+		if info.Name == "remove" {
+			if strings.Join(info.Aliases, ",") != "rm,del" {
+				t.Fatalf("expected aliases [rm del], got %v", info.Aliases)
+			}
+			return
+		}
+	}
+	t.Fatalf("remove command missing from JSON output:\n%s", out)
+}
+
+func Test_DisplayHelp_SingleCommandByAlias(t *testing.T) {
+	out := captureStdout(t, func() {
+		DisplayHelp(os.Stdout, "myapp", aliasHiddenTree(), []string{"rm"})
+	})
+
+	if !strings.Contains(out, "Remove a thing") || !strings.Contains(out, "Aliases: rm, del") {
+		t.Fatalf("expected alias lookup to render the command with its aliases, got:\n%s", out)
+	}
+}
+
+func Test_DisplayError(t *testing.T) {
+	notFound := &cli.UnknownCommandError{Name: "deplyo", Suggestions: []string{"deploy"}}
+
+	var text bytes.Buffer
+	DisplayError(&text, notFound, "plain")
+	if text.String() != "error: unknown command 'deplyo', did you mean 'deploy'?\n" {
+		t.Fatalf("unexpected text error output: %q", text.String())
+	}
+
+	var data bytes.Buffer
+	DisplayError(&data, fmt.Errorf("wrapped: %w", notFound), "json")
+	var info ErrorInfo
+	if err := json.Unmarshal(data.Bytes(), &info); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data.String())
+	}
+	if info.Command != "deplyo" || strings.Join(info.Suggestions, ",") != "deploy" {
+		t.Fatalf("expected the unknown command and its suggestions, got %+v", info)
+	}
+}
+
+func Test_DisplayError_Validation(t *testing.T) {
+	invalid := &cli.ValidationError{Fields: []cli.FieldError{
+		{Field: "Port", Flag: "--port", Short: "-p", Position: -1, Env: "PORT", Rule: "required", Message: "is required"},
+		{Field: "Mode", Flag: "--mode", Position: -1, Rule: "oneof", Value: "fast", Message: "must be one of dev, prod"},
+	}}
+	err := fmt.Errorf("failed to validate command %q: %w", "serve", invalid)
+
+	var text bytes.Buffer
+	DisplayError(&text, err, "plain")
+	want := "error: validation failed\n  --port (-p, $PORT) is required\n  --mode must be one of dev, prod, got \"fast\"\n"
+	if text.String() != want {
+		t.Fatalf("unexpected text error output: %q", text.String())
+	}
+
+	var data bytes.Buffer
+	DisplayError(&data, err, "json")
+	var info ErrorInfo
+	if err := json.Unmarshal(data.Bytes(), &info); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data.String())
+	}
+	if len(info.Fields) != 2 || info.Fields[0].Flag != "--port" || info.Fields[1].Rule != "oneof" {
+		t.Fatalf("expected the failed fields, got %+v", info.Fields)
+	}
+}
+
+type deprecatedFlags struct {
+	Region    string `arg:"region" help:"deploy region"`
+	OldRegion string `arg:"old-region" help:"deploy region" deprecated:"renamed" replacement:"region"`
+}
+
+type deprecatedStub struct {
+	cli.BaseCommand[deprecatedFlags]
+	help string
+}
+
+func (s *deprecatedStub) Run(_ cli.GlobalFlags, _ cli.Unknowns) error { return nil }
+func (s *deprecatedStub) Help() string                                { return s.help }
+
+// deprecationTree is commandTree plus a command with a deprecated flag and a deprecated command.
+func deprecationTree() []cli.Command[any] {
+	ship := &deprecatedStub{BaseCommand: cli.NewBaseCommand[deprecatedFlags](), help: "Ship it"}
+	ship.Name("ship")
+	push := &flagStub{BaseCommand: cli.NewBaseCommand[testFlags](), help: "Push it"}
+	push.Name("push")
+	push.Deprecate("will be removed in v2", "ship")
+	return append(commandTree(), ship, push)
+}
+
+func Test_Help_DeprecatedHiddenFromText(t *testing.T) {
+	renders := map[string]func(){
+		"text":       func() { DisplayHelp(os.Stdout, "myapp", deprecationTree(), nil, DisplayOptions{ShowFlags: true}) },
+		"text-flags": func() { DisplayHelp(os.Stdout, "myapp", deprecationTree(), []string{"ship"}) },
+		"agent": func() {
+			DisplayHelpAgent(os.Stdout, AgentOptions{AppName: "myapp", Format: "plain", Commands: deprecationTree()})
+		},
+	}
+
+	for name, render := range renders {
+		t.Run(name, func(t *testing.T) {
+			out := captureStdout(t, render)
+			if strings.Contains(out, "Push it") || strings.Contains(out, "old-region") {
+				t.Fatalf("expected deprecated items to be left out, got:\n%s", out)
+			}
+			if !strings.Contains(out, "region") {
+				t.Fatalf("expected the replacement flag to be listed, got:\n%s", out)
+			}
+		})
+	}
+}
+
+func Test_DisplayHelp_SingleDeprecatedCommand(t *testing.T) {
+	out := captureStdout(t, func() {
+		DisplayHelp(os.Stdout, "myapp", deprecationTree(), []string{"push"})
+	})
+
+	if !strings.Contains(out, "Deprecated: use 'ship' instead: will be removed in v2") {
+		t.Fatalf("expected the deprecation to be shown, got:\n%s", out)
+	}
+}
+
+func Test_DisplayHelpJSON_Deprecated(t *testing.T) {
+	out := captureStdout(t, func() {
+		DisplayHelpJSON(os.Stdout, deprecationTree())
+	})
+
+	var infos []CommandInfo
+	if err := json.Unmarshal([]byte(out), &infos); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	byName := map[string]CommandInfo{}
+	for _, info := range infos {
+		byName[info.Name] = info
+	}
+
+	push := byName["push"].Deprecated
+	if push == nil || push.Replacement != "ship" || push.Message != "will be removed in v2" {
+		t.Fatalf("expected push to be marked deprecated, got %+v", push)
+	}
+	if byName["ship"].Deprecated != nil {
+		t.Fatalf("expected ship not to be deprecated")
+	}
+	for _, flag := range byName["ship"].Flags {
+		if flag.Name == "old-region" && (flag.Deprecated == nil || flag.Deprecated.Replacement != "region") {
+			t.Fatalf("expected old-region to be marked deprecated, got %+v", flag.Deprecated)
+		}
+		if flag.Name == "region" && flag.Deprecated != nil {
+			t.Fatalf("expected region not to be deprecated")
+		}
+	}
+}
+
+func Test_DisplayHelpJSONSchema_Deprecated(t *testing.T) {
+	out := captureStdout(t, func() {
+		DisplayHelpJSONSchema(os.Stdout, deprecationTree())
+	})
+
+	var schemas []CommandSchema
+	if err := json.Unmarshal([]byte(out), &schemas); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	for _, s := range schemas {
+		switch s.Name {
+		case "push":
+			if !s.Deprecated {
+				t.Fatalf("expected push schema to be deprecated")
+			}
+		case "ship":
+			if !s.Properties["old-region"].Deprecated || s.Properties["region"].Deprecated {
+				t.Fatalf("expected only old-region to be deprecated, got %+v", s.Properties)
+			}
+		}
+	}
+}
+
+type connFlags struct {
+	DSN string `arg:"dsn" env:"DSN" help:"database DSN"`
+}
+
+type persistentParent struct {
+	cli.BaseCommand[connFlags]
+}
+
+func (s *persistentParent) Run(_ cli.GlobalFlags, _ cli.Unknowns) error { return nil }
+func (s *persistentParent) Help() string                                { return "Database commands" }
+func (s *persistentParent) PersistentOptions() any                      { return s.Options() }
+
+// persistentTree is a db parent handing --dsn down to its migrate subcommand.
+func persistentTree() []cli.Command[any] {
+	db := &persistentParent{BaseCommand: cli.NewBaseCommand[connFlags]()}
+	db.Name("db")
+	db.Add("migrate", &flagStub{BaseCommand: cli.NewBaseCommand[testFlags](), help: "Run migrations"})
+	return []cli.Command[any]{db}
+}
+
+func Test_Help_InheritedFlags(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		out := captureStdout(t, func() {
+			DisplayHelp(os.Stdout, "myapp", persistentTree(), []string{"db", "migrate"})
+		})
+		i := strings.Index(out, "Inherited Options:")
+		if i < 0 || !strings.Contains(out[i:], "--dsn") {
+			t.Fatalf("expected --dsn under Inherited Options, got:\n%s", out)
+		}
+	})
+
+	t.Run("agent", func(t *testing.T) {
+		out := captureStdout(t, func() {
+			DisplayHelpAgent(os.Stdout, AgentOptions{AppName: "myapp", Format: "plain", Commands: persistentTree()})
+		})
+		i := strings.Index(out, "db migrate")
+		if i < 0 || !strings.Contains(out[i:], "Inherited flags:") || !strings.Contains(out[i:], "--dsn") {
+			t.Fatalf("expected migrate to list --dsn as inherited, got:\n%s", out)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out := captureStdout(t, func() { DisplayHelpJSON(os.Stdout, persistentTree()) })
+		var infos []CommandInfo
+		if err := json.Unmarshal([]byte(out), &infos); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(infos[0].InheritedFlags) != 0 {
+			t.Fatalf("expected the parent to inherit nothing, got %+v", infos[0].InheritedFlags)
+		}
+		migrate := infos[0].SubCommands[0]
+		if len(migrate.InheritedFlags) != 1 || migrate.InheritedFlags[0].Name != "dsn" || migrate.InheritedFlags[0].Env != "DSN" {
+			t.Fatalf("expected migrate to inherit --dsn, got %+v", migrate.InheritedFlags)
+		}
+	})
+
+	t.Run("jsonschema", func(t *testing.T) {
+		out := captureStdout(t, func() { DisplayHelpJSONSchema(os.Stdout, persistentTree()) })
+		var schemas []CommandSchema
+		if err := json.Unmarshal([]byte(out), &schemas); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		for _, s := range schemas {
+			if s.Name == "db migrate" {
+				if _, ok := s.InheritedProperties["dsn"]; !ok {
+					t.Fatalf("expected dsn in inheritedProperties, got %+v", s.InheritedProperties)
+				}
+				return
+			}
+		}
+		t.Fatalf("db migrate schema missing:\n%s", out)
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		out := captureStdout(t, func() {
+			DisplayHelpJSON(os.Stdout, FilterCommands(persistentTree(), []string{"db", "migrate"}))
+		})
+		if !strings.Contains(out, `"inheritedFlags"`) {
+			t.Fatalf("expected a filtered tree to keep inherited flags, got:\n%s", out)
+		}
+	})
+}
+
+func Test_Help_DerivedEnvNames(t *testing.T) {
+	app := cli.NewApp(cli.Config{Name: "myapp", EnvPrefix: "MYAPP"}, cli.GlobalFlags{})
+	app.Add("greet", &flagStub{BaseCommand: cli.NewBaseCommand[testFlags](), help: "Greet"})
+
+	var text bytes.Buffer
+	DisplayHelp(&text, "myapp", app.Commands(), nil, DisplayOptions{ShowFlags: true, ShowEnv: true})
+	if !strings.Contains(text.String(), "[env: MYAPP_VERBOSE]") || !strings.Contains(text.String(), "[env: NAME]") {
+		t.Fatalf("expected derived and explicit env names, got:\n%s", text.String())
+	}
+
+	var data bytes.Buffer
+	DisplayHelpJSON(&data, app.Commands())
+	var infos []CommandInfo
+	if err := json.Unmarshal(data.Bytes(), &infos); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	envs := map[string]string{}
+	for _, flag := range infos[0].Flags {
+		envs[flag.Name] = flag.Env
+	}
+	if envs["verbose"] != "MYAPP_VERBOSE" || envs["name"] != "NAME" {
+		t.Fatalf("expected derived and explicit env names, got %v", envs)
+	}
+}
+
+func Test_Help_Passthrough(t *testing.T) {
+	exec := &flagStub{BaseCommand: cli.NewBaseCommand[testFlags](), help: "Run a program"}
+	exec.Name("exec")
+	exec.AcceptPassthrough()
+	commands := []cli.Command[any]{exec, newFlagStub("build", "Build the project")}
+
+	var text bytes.Buffer
+	DisplayHelp(&text, "myapp", commands, []string{"exec"})
+	if !strings.Contains(text.String(), "$ exec [-- args...]") {
+		t.Fatalf("expected the passthrough usage, got:\n%s", text.String())
+	}
+	text.Reset()
+	DisplayHelp(&text, "myapp", FilterCommands(commands, []string{"build"}), []string{"build"})
+	if strings.Contains(text.String(), "[-- args...]") {
+		t.Fatalf("expected no passthrough usage for build, got:\n%s", text.String())
+	}
+
+	var agent bytes.Buffer
+	DisplayHelpAgent(&agent, AgentOptions{AppName: "myapp", Format: "plain", Commands: commands})
+	if !strings.Contains(agent.String(), "Usage: myapp exec [-- args...]") {
+		t.Fatalf("expected the passthrough usage, got:\n%s", agent.String())
+	}
+
+	var data bytes.Buffer
+	DisplayHelpJSON(&data, commands)
+	var infos []CommandInfo
+	if err := json.Unmarshal(data.Bytes(), &infos); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !infos[0].Passthrough || infos[1].Passthrough {
+		t.Fatalf("expected only exec to take passthrough args, got %+v", infos)
+	}
+}
+
+type negatableFlags struct {
+	Color   bool `arg:"color" short:"c" default:"true" help:"colorize output"`
+	Verbose bool `arg:"verbose" help:"enable verbose output"`
+}
+
+type negatableStub struct {
+	cli.BaseCommand[negatableFlags]
+}
+
+func (s *negatableStub) Run(_ cli.GlobalFlags, _ cli.Unknowns) error { return nil }
+func (s *negatableStub) Help() string                                { return "Paint things" }
+
+func Test_Help_Negatable(t *testing.T) {
+	paint := &negatableStub{BaseCommand: cli.NewBaseCommand[negatableFlags]()}
+	paint.Name("paint")
+	commands := []cli.Command[any]{paint}
+
+	var text bytes.Buffer
+	DisplayHelp(&text, "myapp", commands, []string{"paint"})
+	if !strings.Contains(text.String(), "-c, --[no-]color") || strings.Contains(text.String(), "[no-]verbose") {
+		t.Fatalf("expected only color shown negatable, got:\n%s", text.String())
+	}
+
+	var agent bytes.Buffer
+	DisplayHelpAgent(&agent, AgentOptions{AppName: "myapp", Format: "plain", Commands: commands})
+	if !strings.Contains(agent.String(), "--[no-]color, -c") {
+		t.Fatalf("expected color shown negatable, got:\n%s", agent.String())
+	}
+
+	var data bytes.Buffer
+	DisplayHelpJSON(&data, commands)
+	var infos []CommandInfo
+	if err := json.Unmarshal(data.Bytes(), &infos); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if flags := infos[0].Flags; !flags[0].Negatable || flags[0].Name != "color" || flags[1].Negatable {
+		t.Fatalf("expected only color marked negatable, got %+v", flags)
+	}
+}
+
+type counterFlags struct {
+	cli.Verbosity
+	Name string `arg:"name" help:"the name to use"`
+}
+
+type counterStub struct {
+	cli.BaseCommand[counterFlags]
+}
+
+func (s *counterStub) Run(_ cli.GlobalFlags, _ cli.Unknowns) error { return nil }
+func (s *counterStub) Help() string                                { return "Log things" }
+
+func Test_Help_Counter(t *testing.T) {
+	logs := &counterStub{BaseCommand: cli.NewBaseCommand[counterFlags]()}
+	logs.Name("logs")
+	commands := []cli.Command[any]{logs}
+
+	var text bytes.Buffer
+	DisplayHelp(&text, "myapp", commands, []string{"logs"})
+	if !strings.Contains(text.String(), "-v, --verbose...") {
+		t.Fatalf("expected verbose shown repeatable, got:\n%s", text.String())
+	}
+
+	var agent bytes.Buffer
+	DisplayHelpAgent(&agent, AgentOptions{AppName: "myapp", Format: "plain", Commands: commands})
+	if !strings.Contains(agent.String(), "--verbose..., -v  count") {
+		t.Fatalf("expected verbose shown as a repeatable count, got:\n%s", agent.String())
+	}
+
+	var data bytes.Buffer
+	DisplayHelpJSON(&data, commands)
+	var infos []CommandInfo
+	if err := json.Unmarshal(data.Bytes(), &infos); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if flags := infos[0].Flags; flags[0].Name != "verbose" || !flags[0].Repeatable || flags[1].Repeatable {
+		t.Fatalf("expected only verbose marked repeatable, got %+v", flags)
+	}
+}
+
+type relationFlags struct {
+	File   string `arg:"file" exclusive:"source" help:"read from a file"`
+	Stdin  bool   `arg:"stdin" exclusive:"source" help:"read from stdin"`
+	ID     string `arg:"id" onerequired:"target" help:"target by id"`
+	Name   string `arg:"name" onerequired:"target" help:"target by name"`
+	Key    string `arg:"tls-key" requires:"tls-cert" help:"TLS key"`
+	Cert   string `arg:"tls-cert" help:"TLS certificate"`
+	Auth   string `arg:"auth" help:"auth method"`
+	Secret string `arg:"token" requiredif:"auth=token" help:"auth token"`
+}
+
+type relationStub struct {
+	cli.BaseCommand[relationFlags]
+}
+
+func (s *relationStub) Run(_ cli.GlobalFlags, _ cli.Unknowns) error { return nil }
+func (s *relationStub) Help() string                                { return "Fetch things" }
+
+func Test_Help_Relations(t *testing.T) {
+	fetch := &relationStub{BaseCommand: cli.NewBaseCommand[relationFlags]()}
+	fetch.Name("fetch")
+	commands := []cli.Command[any]{fetch}
+
+	notes := []string{
+		"read from a file (conflicts with --stdin)",
+		"target by name (exactly one of --id, --name)",
+		"TLS key (requires --tls-cert)",
+		"auth token (required if --auth=token)",
+	}
+	var text bytes.Buffer
+	DisplayHelp(&text, "myapp", commands, []string{"fetch"})
+	var agent bytes.Buffer
+	DisplayHelpAgent(&agent, AgentOptions{AppName: "myapp", Format: "plain", Commands: commands})
+	for _, note := range notes {
+		if !strings.Contains(text.String(), note) {
+			t.Fatalf("expected %q in text help, got:\n%s", note, text.String())
+		}
+		if !strings.Contains(agent.String(), note) {
+			t.Fatalf("expected %q in agent help, got:\n%s", note, agent.String())
+		}
+	}
+
+	var data bytes.Buffer
+	DisplayHelpJSON(&data, commands)
+	var infos []CommandInfo
+	if err := json.Unmarshal(data.Bytes(), &infos); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got := len(infos[0].Relations); got != 4 {
+		t.Fatalf("expected 4 relations, got %+v", infos[0].Relations)
+	}
+
+	var schema bytes.Buffer
+	DisplayHelpJSONSchema(&schema, commands)
+	for _, want := range []string{
+		`"dependencies": {`,
+		`"tls-key": {
+        "required": [
+          "tls-cert"
+        ]
+      }`,
+		`"not": {
+          "anyOf": [
+            {
+              "required": [
+                "stdin"
+              ]`,
+		`"oneOf": [
+      {
+        "required": [
+          "id"
+        ]
+      },`,
+		`"if": {
+          "required": [
+            "auth"
+          ],
+          "properties": {
+            "auth": {
+              "const": "token"
+            }
+          }
+        },
+        "then": {
+          "required": [
+            "token"
+          ]
+        }`,
+	} {
+		if !strings.Contains(schema.String(), want) {
+			t.Fatalf("expected %s in the schema, got:\n%s", want, schema.String())
+		}
+	}
+}
+
+type valueFlags struct {
+	Timeout time.Duration `arg:"timeout" help:"request timeout"`
+	Limit   cli.Size      `arg:"limit" help:"body limit"`
+	Bind    net.IP        `arg:"bind" help:"address to bind"`
+}
+
+type valueStub struct {
+	cli.BaseCommand[valueFlags]
+}
+
+func (s *valueStub) Run(_ cli.GlobalFlags, _ cli.Unknowns) error { return nil }
+func (s *valueStub) Help() string                                { return "Serve things" }
+
+func Test_Help_ValueTypes(t *testing.T) {
+	serve := &valueStub{BaseCommand: cli.NewBaseCommand[valueFlags]()}
+	serve.Name("serve")
+	serve.Inputs = &valueFlags{Timeout: 30 * time.Second}
+	commands := []cli.Command[any]{serve}
+
+	var text bytes.Buffer
+	DisplayHelp(&text, "myapp", commands, []string{"serve"}, DisplayOptions{ShowValues: true})
+	if !strings.Contains(text.String(), "duration 30s") {
+		t.Fatalf("expected the duration type and value, got:\n%s", text.String())
+	}
+
+	var agent bytes.Buffer
+	DisplayHelpAgent(&agent, AgentOptions{AppName: "myapp", Format: "plain", Commands: commands})
+	for _, want := range []string{"--timeout  duration", "--limit    size", "--bind     ip"} {
+		if !strings.Contains(agent.String(), want) {
+			t.Fatalf("expected %q, got:\n%s", want, agent.String())
+		}
+	}
+
+	var data bytes.Buffer
+	DisplayHelpJSON(&data, commands)
+	var infos []CommandInfo
+	if err := json.Unmarshal(data.Bytes(), &infos); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got := infos[0].Flags[0].Type; got != "duration" {
+		t.Fatalf("expected the duration type, got %q", got)
+	}
+
+	var schema bytes.Buffer
+	DisplayHelpJSONSchema(&schema, commands)
+	var schemas []CommandSchema
+	if err := json.Unmarshal(schema.Bytes(), &schemas); err != nil {
+		t.Fatalf("invalid JSON schema: %v", err)
+	}
+	if got := schemas[0].Properties["timeout"]; got.Type != "string" || got.Format != "duration" {
+		t.Fatalf("expected a duration-formatted string, got %+v", got)
+	}
+}
+
+type mapFlags struct {
+	Labels   map[string]string        `arg:"label" help:"labels to apply"`
+	Limits   map[string]int           `arg:"limit" help:"resource limits"`
+	Timeouts map[string]time.Duration `arg:"timeout" help:"per-service timeouts"`
+}
+
+type mapStub struct {
+	cli.BaseCommand[mapFlags]
+}
+
+func (s *mapStub) Run(_ cli.GlobalFlags, _ cli.Unknowns) error { return nil }
+func (s *mapStub) Help() string                                { return "Deploy things" }
+
+func Test_Help_MapFlags(t *testing.T) {
+	deploy := &mapStub{BaseCommand: cli.NewBaseCommand[mapFlags]()}
+	deploy.Name("deploy")
+	deploy.Inputs = &mapFlags{Labels: map[string]string{"team": "core", "env": "prod"}}
+	commands := []cli.Command[any]{deploy}
+
+	var text bytes.Buffer
+	DisplayHelp(&text, "myapp", commands, []string{"deploy"}, DisplayOptions{ShowValues: true})
+	if !strings.Contains(text.String(), "map[string]string env=prod,team=core") {
+		t.Fatalf("expected the map type and its entries sorted, got:\n%s", text.String())
+	}
+
+	var schema bytes.Buffer
+	DisplayHelpJSONSchema(&schema, commands)
+	var schemas []CommandSchema
+	if err := json.Unmarshal(schema.Bytes(), &schemas); err != nil {
+		t.Fatalf("invalid JSON schema: %v", err)
+	}
+	got := schemas[0].Properties["limit"]
+	if got.Type != "object" || got.AdditionalProperties == nil || got.AdditionalProperties.Type != "number" {
+		t.Fatalf("expected an object of numbers, got %+v", got)
+	}
+	got = schemas[0].Properties["timeout"]
+	if elem := got.AdditionalProperties; elem == nil || elem.Type != "string" || elem.Format != "duration" {
+		t.Fatalf("expected an object of duration-formatted strings, got %+v", got.AdditionalProperties)
+	}
+}
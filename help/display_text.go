@@ -58,16 +58,30 @@ func DisplayHelp(w io.Writer, appName string, commands []cli.Command[any], comma
 
 // findCommandByArgs walks the command tree to find the command matching the arg path.
 func findCommandByArgs(commands []cli.Command[any], args []string) cli.Command[any] {
+	path := findCommandPath(commands, args)
+	if path == nil {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+// findCommandPath walks the command tree along the arg path and returns the commands on it, root first,
+// or nil when the path does not resolve.
+func findCommandPath(commands []cli.Command[any], args []string) []cli.Command[any] {
 	if len(args) == 0 {
 		return nil
 	}
 
 	for _, cmd := range commands {
-		if cmd.Name("") == args[0] {
+		if commandMatches(cmd, args[0]) {
 			if len(args) == 1 {
-				return cmd
+				return []cli.Command[any]{cmd}
+			}
+			rest := findCommandPath(cmd.Commands(), args[1:])
+			if rest == nil {
+				return nil
 			}
-			return findCommandByArgs(cmd.Commands(), args[1:])
+			return append([]cli.Command[any]{cmd}, rest...)
 		}
 	}
 
@@ -79,35 +93,54 @@ func displaySingleCommandHelp(w io.Writer, appName string, commands []cli.Comman
 		`Usage: ` + appName + ` <command> <subcommand> [args] [options]`,
 	}
 
-	cmd := findCommandByArgs(commands, command)
-	if cmd == nil {
+	path := findCommandPath(commands, command)
+	if path == nil {
 		_, _ = fmt.Fprintln(w, "Command not found")
 		return []string{}
 	}
+	cmd := path[len(path)-1]
 
 	cmdHelp := cmd.Help()
 	if cmdHelp != "" {
 		help = append(help, cmdHelp)
 	}
+	if aliases := cli.CommandAliases(cmd); len(aliases) > 0 {
+		help = append(help, `Aliases: `+strings.Join(aliases, ", "))
+	}
+	if dep := cli.CommandDeprecation(cmd); dep.IsDeprecated() {
+		help = append(help, `Deprecated: `+deprecationText(dep))
+	}
 	if desc := commandDescription(cmd); desc != "" {
 		help = append(help, ``)
 		help = append(help, strings.Split(desc, "\n")...)
 	}
 	help = append(help, ``)
 	line := `$ ` + strings.Join(command, " ")
+	if acceptsPassthrough(cmd) {
+		line += " " + passthroughUsage
+	}
 	help = append(help, line)
 
-	options, _ := helpOptionsWithEnv(cmd.Options(), false, opts.ShowValues, nil)
+	options := printableFieldsWithEnv(currentFields(commandFields(cmd)), false, opts.ShowValues, nil)
 	if len(options) > 0 {
 		help = append(help, options...)
 	}
 
+	var inherited []structs.Field
+	for _, ancestor := range path[:len(path)-1] {
+		inherited = inheritedFields(ancestor, inherited)
+	}
+	if lines := printableFieldsWithEnv(currentFields(inherited), false, opts.ShowValues, nil); len(lines) > 0 {
+		help = append(help, ``, `Inherited Options:`)
+		help = append(help, lines...)
+	}
+
 	help = append(help, providerDocLines(cmd, "")...)
 
-	if len(cmd.Commands()) > 0 {
-		longestName := getLongestName(cmd.Commands())
-		for _, subCmd := range cmd.Commands() {
-			name := subCmd.Name("")
+	if subs := listedCommands(cmd.Commands()); len(subs) > 0 {
+		longestName := getLongestName(subs)
+		for _, subCmd := range subs {
+			name := commandLabel(subCmd.Name(""), subCmd)
 			help = append(help, fmt.Sprintf(`  %s  %s%s`, name, pad(name, longestName), firstLine(subCmd.Help())))
 
 			if opts.ShowFlags {
@@ -139,19 +172,20 @@ func displayAllCommandsHelp(appName string, commands []cli.Command[any], opts Di
 
 	help = append(help, ``, `Commands:`)
 
+	commands = listedCommands(commands)
 	longestName := getLongestName(commands)
 
 	for _, cmd := range commands {
-		name := cmd.Name("")
-		help = append(help, fmt.Sprintf(`  %s  %s%s`, name, pad(name, longestName), firstLine(cmd.Help())))
+		label := commandLabel(cmd.Name(""), cmd)
+		help = append(help, fmt.Sprintf(`  %s  %s%s`, label, pad(label, longestName), firstLine(cmd.Help())))
 
 		if opts.ShowFlags {
 			help = appendCommandFlags(help, cmd, opts)
 		}
 
-		if len(cmd.Commands()) > 0 {
-			for _, subCmd := range cmd.Commands() {
-				subName := name + " " + subCmd.Name("")
+		if subs := listedCommands(cmd.Commands()); len(subs) > 0 {
+			for _, subCmd := range subs {
+				subName := commandLabel(cmd.Name("")+" "+subCmd.Name(""), subCmd)
 				help = append(help, `  `+subName+``+pad(subName, longestName)+`  `+firstLine(subCmd.Help()))
 
 				if opts.ShowFlags {
@@ -165,8 +199,8 @@ func displayAllCommandsHelp(appName string, commands []cli.Command[any], opts Di
 }
 
 func appendCommandFlags(help []string, cmd cli.Command[any], opts DisplayOptions) []string {
-	cmdOpts, err := helpOptionsWithEnv(cmd.Options(), opts.ShowEnv, opts.ShowValues, nil)
-	if err != nil || len(cmdOpts) == 0 {
+	cmdOpts := printableFieldsWithEnv(currentFields(commandFields(cmd)), opts.ShowEnv, opts.ShowValues, nil)
+	if len(cmdOpts) == 0 {
 		return help
 	}
 
@@ -181,13 +215,13 @@ func getLongestName(commands []cli.Command[any]) int {
 	longestName := 0
 
 	for _, cmd := range commands {
-		name := cmd.Name("")
+		name := commandLabel(cmd.Name(""), cmd)
 		if len(name) > longestName {
 			longestName = len(name)
 		}
-		if len(cmd.Commands()) > 0 {
-			for _, subCmd := range cmd.Commands() {
-				subName := name + " " + subCmd.Name("")
+		if subs := listedCommands(cmd.Commands()); len(subs) > 0 {
+			for _, subCmd := range subs {
+				subName := commandLabel(cmd.Name("")+" "+subCmd.Name(""), subCmd)
 				if len(subName) > longestName {
 					longestName = len(subName)
 				}
@@ -210,7 +244,7 @@ func flagShortPrefix(short string) string {
 // shortColWidth is the width of the reserved short-flag column: the widest "-x, " prefix across
 // all fields and their nested sub-fields. Flags without a short are left-padded to this width so
 // every "--long" name lines up, the way Cobra/clap/Click render their option lists. Multi-letter
-// shorts (e.g. -vv, -vvv from cli.Verbosity) widen the column accordingly. Returns 0 when no field
+// shorts (e.g. -vv) widen the column accordingly. Returns 0 when no field
 // has a short, so flags render flush-left with no reserved column.
 func shortColWidth(fields []structs.Field) int {
 	w := 0
@@ -242,6 +276,7 @@ func printableFieldsWithEnv(fields []structs.Field, showEnv, showValues bool, ex
 	lines := []string{}
 	shortColW := shortColWidth(fields)
 	longestArg := maxLen(fields, shortColW)
+	notes := relationNotes(fields)
 
 	// resolved values get their own aligned column between the flag and the description
 	// (rather than trailing after the help text), to match the tables.
@@ -261,9 +296,9 @@ func printableFieldsWithEnv(fields []structs.Field, showEnv, showValues bool, ex
 		if isPositionalArg(field.Tags["arg"]) {
 			continue
 		}
-		args := flagArgs(field.Tags["arg"], field.Tags["short"], shortColW)
+		args := flagArgs(displayArg(field, field.Tags["arg"]), field.Tags["short"], shortColW)
 
-		helpText := withAllowedValues(field.Tags["help"], field, formatHintExtras(field, extraFormats))
+		helpText := withRelationNote(withAllowedValues(field.Tags["help"], field, formatHintExtras(field, extraFormats)), notes[flagArg(field)])
 		if showEnv && field.Tags["env"] != "" {
 			helpText += fmt.Sprintf(" [env: %s]", field.Tags["env"])
 		}
@@ -287,9 +322,9 @@ func printableFieldsWithEnv(fields []structs.Field, showEnv, showValues bool, ex
 		lines = append(lines, strings.TrimRight(line, " "))
 
 		for _, subField := range field.Fields {
-			subArgs := flagArgs(subField.Tags["arg"], subField.Tags["short"], shortColW)
+			subArgs := flagArgs(displayArg(subField, subField.Tags["arg"]), subField.Tags["short"], shortColW)
 			padding := pad(subArgs, longestArg)
-			subHelp := "  - " + withAllowedValues(subField.Tags["help"], subField, formatHintExtras(subField, extraFormats))
+			subHelp := "  - " + withRelationNote(withAllowedValues(subField.Tags["help"], subField, formatHintExtras(subField, extraFormats)), notes[flagArg(subField)])
 			if showEnv && subField.Tags["env"] != "" {
 				subHelp += fmt.Sprintf(" [env: %s]", subField.Tags["env"])
 			}
@@ -305,11 +340,11 @@ func maxLen(fields []structs.Field, shortColW int) int {
 	longestArg := 0
 
 	for _, field := range fields {
-		if n := len(flagArgs(field.Tags["arg"], field.Tags["short"], shortColW)); n > longestArg {
+		if n := len(flagArgs(displayArg(field, field.Tags["arg"]), field.Tags["short"], shortColW)); n > longestArg {
 			longestArg = n
 		}
 		for _, subField := range field.Fields {
-			if n := len(flagArgs(subField.Tags["arg"], subField.Tags["short"], shortColW)); n > longestArg {
+			if n := len(flagArgs(displayArg(subField, subField.Tags["arg"]), subField.Tags["short"], shortColW)); n > longestArg {
 				longestArg = n
 			}
 		}
@@ -325,7 +360,7 @@ func helpOptionsWithEnv(structure any, showEnv, showValues bool, extraFormats []
 		return nil, fmt.Errorf("failed to get struct fields: %w", err)
 	}
 
-	return printableFieldsWithEnv(fields, showEnv, showValues, extraFormats), nil
+	return printableFieldsWithEnv(currentFields(fields), showEnv, showValues, extraFormats), nil
 }
 
 func pad(text string, indent int) string {
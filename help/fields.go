@@ -29,6 +29,131 @@ func commandDescription(cmd cli.Command[any]) string {
 	return strings.TrimRight(cmd.Description(), "\n")
 }
 
+// passthroughUsage is how help shows that a command takes arguments after a "--" terminator.
+const passthroughUsage = "[-- args...]"
+
+// acceptsPassthrough reports whether cmd takes arguments after "--" (see BaseCommand.AcceptPassthrough).
+func acceptsPassthrough(cmd cli.Command[any]) bool {
+	p, ok := cmd.(cli.PassthroughAccepter)
+	return ok && p.AcceptsPassthrough()
+}
+
+// negationMark is put in front of the long name of a flag that also takes the "--no-<name>" form.
+const negationMark = "[no-]"
+
+// repeatMark follows the long name of a counter flag, which adds up every time it is given.
+const repeatMark = "..."
+
+// displayArg is the long name help shows for field: "[no-]color" when its negation is advertised
+// (see cli.FieldNegatable), "verbose..." for a counter (see cli.FieldCounter), the bare arg otherwise.
+func displayArg(field structs.Field, arg string) string {
+	switch {
+	case arg == "":
+		return arg
+	case cli.FieldNegatable(field):
+		return negationMark + arg
+	case cli.FieldCounter(field):
+		return arg + repeatMark
+	}
+	return arg
+}
+
+// visibleCommands returns commands without the hidden ones, so every listing skips them
+// while they stay runnable by name.
+func visibleCommands(commands []cli.Command[any]) []cli.Command[any] {
+	visible := make([]cli.Command[any], 0, len(commands))
+	for _, cmd := range commands {
+		if !cli.CommandHidden(cmd) {
+			visible = append(visible, cmd)
+		}
+	}
+	return visible
+}
+
+// listedCommands returns the commands human-readable listings show: visible and not deprecated.
+// Deprecated commands still run (after a warning) and stay in the JSON help, marked.
+func listedCommands(commands []cli.Command[any]) []cli.Command[any] {
+	listed := make([]cli.Command[any], 0, len(commands))
+	for _, cmd := range visibleCommands(commands) {
+		if !cli.CommandDeprecation(cmd).IsDeprecated() {
+			listed = append(listed, cmd)
+		}
+	}
+	return listed
+}
+
+// currentFields returns fields without the deprecated ones, at every nesting level,
+// so human-readable flag listings steer users to the replacements.
+func currentFields(fields []structs.Field) []structs.Field {
+	current := make([]structs.Field, 0, len(fields))
+	for _, field := range fields {
+		if cli.FieldDeprecation(field).IsDeprecated() {
+			continue
+		}
+		field.Fields = currentFields(field.Fields)
+		current = append(current, field)
+	}
+	return current
+}
+
+// deprecationText renders a deprecation for help, e.g. "use 'db up' instead: will be removed in v2".
+func deprecationText(dep cli.Deprecation) string {
+	var parts []string
+	if dep.Replacement != "" {
+		parts = append(parts, "use '"+dep.Replacement+"' instead")
+	}
+	if dep.Message != "" {
+		parts = append(parts, dep.Message)
+	}
+	return strings.Join(parts, ": ")
+}
+
+// commandFields returns the fields of cmd's options with the env names the app binds them to
+// (explicit, derived from cli.Config.EnvPrefix, or none; see cli.Fields), or nil when it has no options.
+func commandFields(cmd cli.Command[any]) []structs.Field {
+	if cmd.Options() == nil {
+		return nil
+	}
+	fields, err := cli.Fields(cmd)
+	if err != nil {
+		return nil
+	}
+	return fields
+}
+
+// inheritedFields returns the flags cmd hands down to its subcommands (see cli.Persistent) followed by
+// those inherited, the list a subcommand of cmd inherits, nearest ancestor first.
+func inheritedFields(cmd cli.Command[any], inherited []structs.Field) []structs.Field {
+	own, err := cli.PersistentFields(cmd)
+	if err != nil || len(own) == 0 {
+		return inherited
+	}
+	return append(own, inherited...)
+}
+
+// commandMatches reports whether name is cmd's name or one of its aliases, mirroring how the app dispatches.
+func commandMatches(cmd cli.Command[any], name string) bool {
+	if cmd.Name("") == name {
+		return true
+	}
+	for _, alias := range cli.CommandAliases(cmd) {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// commandLabel is the listing label for cmd shown under path (its full name, e.g. "db migrate"):
+// the path followed by the command's aliases in parentheses, e.g. "remove (rm, del)".
+func commandLabel(path string, cmd cli.Command[any]) string {
+	aliases := cli.CommandAliases(cmd)
+	if len(aliases) == 0 {
+		return path
+	}
+	return path + " (" + strings.Join(aliases, ", ") + ")"
+}
+
 // firstLine returns the first line of s, used to keep listing columns aligned
 // even if a command's Help summary accidentally spans multiple lines.
 func firstLine(s string) string {
@@ -94,9 +219,15 @@ func formatHintExtras(field structs.Field, extraFormats []string) []string {
 }
 
 // displayType renders a field's type for help output, preferring the concrete Go type
-// for slices ("[]string") over the bare reflect kind ("slice").
+// for slices and maps ("[]string", "map[string]int") over the bare reflect kind ("slice").
 func displayType(field structs.Field) string {
-	if field.Value.IsValid() && field.Value.Kind() == reflect.Slice {
+	if cli.FieldCounter(field) {
+		return "count"
+	}
+	if name := cli.FieldTypeName(field); name != "" {
+		return name
+	}
+	if field.Value.IsValid() && (field.Value.Kind() == reflect.Slice || field.Value.Kind() == reflect.Map) {
 		return field.Value.Type().String()
 	}
 	return field.Type
@@ -28,15 +28,14 @@ func FilterCommands(commands []cli.Command[any], filters []string) []cli.Command
 	for _, cmd := range commands {
 		name := cmd.Name("")
 
-		if filterSet[name] {
+		if matchesFilter(filterSet, "", cmd) {
 			result = append(result, cmd)
 			continue
 		}
 
 		var matchedSubs []cli.Command[any]
 		for _, sub := range cmd.Commands() {
-			subPath := name + " " + sub.Name("")
-			if filterSet[subPath] || filterSet[sub.Name("")] {
+			if matchesFilter(filterSet, name+" ", sub) || matchesFilter(filterSet, "", sub) {
 				matchedSubs = append(matchedSubs, sub)
 			}
 		}
@@ -63,7 +62,7 @@ func filterByPath(commands []cli.Command[any], path []string) []cli.Command[any]
 		return nil
 	}
 	for _, cmd := range commands {
-		if cmd.Name("") != path[0] {
+		if !commandMatches(cmd, path[0]) {
 			continue
 		}
 		if len(path) == 1 {
@@ -78,6 +77,19 @@ func filterByPath(commands []cli.Command[any], path []string) []cli.Command[any]
 	return nil
 }
 
+// matchesFilter reports whether prefix plus cmd's name, or plus any of its aliases, is in filterSet.
+func matchesFilter(filterSet map[string]bool, prefix string, cmd cli.Command[any]) bool {
+	if filterSet[prefix+cmd.Name("")] {
+		return true
+	}
+	for _, alias := range cli.CommandAliases(cmd) {
+		if filterSet[prefix+alias] {
+			return true
+		}
+	}
+	return false
+}
+
 // filteredCommand is a command that reports only a subset of its subcommands.
 // It embeds the real command (delegating every method) and overrides Commands(),
 // so FilterCommands can hand the renderers a narrowed view without mutating the
@@ -90,3 +102,24 @@ type filteredCommand struct {
 var _ cli.Command[any] = (*filteredCommand)(nil)
 
 func (f *filteredCommand) Commands() []cli.Command[any] { return f.subs }
+
+// EnvPrefix delegates to the wrapped command, so a narrowed command still lists its derived env names.
+func (f *filteredCommand) EnvPrefix() string {
+	if p, ok := f.Command.(cli.EnvPrefixer); ok {
+		return p.EnvPrefix()
+	}
+	return ""
+}
+
+// AcceptsPassthrough delegates to the wrapped command, so a narrowed command still shows "[-- args...]".
+func (f *filteredCommand) AcceptsPassthrough() bool {
+	return acceptsPassthrough(f.Command)
+}
+
+// PersistentOptions delegates to the wrapped command, so a narrowed parent still hands its flags down.
+func (f *filteredCommand) PersistentOptions() any {
+	if p, ok := f.Command.(cli.Persistent); ok {
+		return p.PersistentOptions()
+	}
+	return nil
+}
@@ -151,3 +151,28 @@ func Test_FilterCommands_DoesNotMutateOriginal(t *testing.T) {
 		t.Fatalf("original command was mutated: expected 2 subs, got %d", len(all[0].Commands()))
 	}
 }
+
+func Test_FilterCommands_Aliases(t *testing.T) {
+	migrate := &stubCommand{help: "Run migrations"}
+	migrate.Name("migrate")
+	migrate.Alias("m")
+	db := &stubCommand{help: "Database"}
+	db.Name("db")
+	db.Alias("d")
+	db.Add("migrate", migrate)
+	build := newStubNamed("build", "Build")
+	all := []cli.Command[any]{build, db}
+
+	byPath := FilterCommands(all, []string{"d", "m"})
+	if len(byPath) != 1 || byPath[0].Name("") != "db" {
+		t.Fatalf("expected alias path to narrow to db, got %d commands", len(byPath))
+	}
+	if subs := byPath[0].Commands(); len(subs) != 1 || subs[0].Name("") != "migrate" {
+		t.Fatalf("expected db narrowed to migrate, got %v", subs)
+	}
+
+	byName := FilterCommands(all, []string{"build", "d"})
+	if len(byName) != 2 {
+		t.Fatalf("expected both commands by name and alias, got %d", len(byName))
+	}
+}
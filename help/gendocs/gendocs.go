@@ -187,11 +187,14 @@ func codecFormatNames(codecs []cli.OutputCodec) []string {
 	return names
 }
 
-// commandPaths walks the command tree and returns the path of every command and subcommand
-// (e.g. ["build"], ["db", "migrate"]), depth-first.
+// commandPaths walks the command tree and returns the path of every visible command and subcommand
+// (e.g. ["build"], ["db", "migrate"]), depth-first. Hidden commands, and everything beneath them, are skipped.
 func commandPaths(commands []cli.Command[any], prefix []string) [][]string {
 	var paths [][]string
 	for _, cmd := range commands {
+		if cli.CommandHidden(cmd) {
+			continue
+		}
 		path := append(append([]string{}, prefix...), cmd.Name(""))
 		paths = append(paths, path)
 		paths = append(paths, commandPaths(cmd.Commands(), path)...)
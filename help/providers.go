@@ -39,7 +39,7 @@ func extractExampleFlags(options any) string {
 	}
 
 	var parts []string
-	for _, field := range fields {
+	for _, field := range currentFields(fields) {
 		arg := field.Tags["arg"]
 		if arg == "" {
 			continue
@@ -55,10 +55,10 @@ func extractExampleFlags(options any) string {
 			continue
 		}
 
-		switch field.Type {
-		case "bool":
-			parts = append(parts, "--"+arg)
-		case "string":
+		switch {
+		case field.Type == "bool" || cli.FieldCounter(field):
+			parts = append(parts, "--"+displayArg(field, arg))
+		case field.Type == "string":
 			parts = append(parts, "--"+arg+"=<value>")
 		default:
 			parts = append(parts, fmt.Sprintf("--%s=<%s>", arg, displayType(field)))
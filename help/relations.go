@@ -0,0 +1,165 @@
+package help
+
+import (
+	"strings"
+
+	"github.com/toaweme/structs"
+
+	"github.com/toaweme/cli"
+)
+
+// relationNotes maps each flag among fields to what its relation rules (see cli.Relation) add to its
+// help text, such as "(requires --tls-cert)", keyed by its fully-qualified arg.
+func relationNotes(fields []structs.Field) map[string]string {
+	notes := make(map[string]string)
+	add := func(flag, note string) {
+		if notes[flag] != "" {
+			note = notes[flag] + " " + note
+		}
+		notes[flag] = note
+	}
+
+	for _, relation := range cli.FieldRelations(fields) {
+		switch relation.Rule {
+		case cli.RuleExclusive:
+			for _, flag := range relation.Flags {
+				if others := dashed(without(relation.Flags, flag)); len(others) > 0 {
+					add(flag, "(conflicts with "+strings.Join(others, ", ")+")")
+				}
+			}
+		case cli.RuleOneRequired:
+			for _, flag := range relation.Flags {
+				add(flag, "(exactly one of "+strings.Join(dashed(relation.Flags), ", ")+")")
+			}
+		case cli.RuleRequires:
+			add(relation.Flag, "(requires "+strings.Join(dashed(relation.Flags), ", ")+")")
+		case cli.RuleRequiredIf:
+			if relation.Value == "" {
+				add(relation.Flag, "(required if --"+relation.Flags[0]+" is set)")
+			} else {
+				add(relation.Flag, "(required if --"+relation.Flags[0]+"="+relation.Value+")")
+			}
+		}
+	}
+
+	return notes
+}
+
+// withRelationNote appends note to help text, separated by a space when both are present.
+func withRelationNote(help, note string) string {
+	if note == "" {
+		return help
+	}
+	if help == "" {
+		return note
+	}
+	return help + " " + note
+}
+
+// dashed returns flags with their "--" prefix, as a user types them.
+func dashed(flags []string) []string {
+	out := make([]string, len(flags))
+	for i, flag := range flags {
+		out[i] = "--" + flag
+	}
+	return out
+}
+
+// without returns flags less flag.
+func without(flags []string, flag string) []string {
+	out := make([]string, 0, len(flags))
+	for _, f := range flags {
+		if f != flag {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// RelationInfo is the serialized representation of a cli.Relation.
+type RelationInfo struct {
+	Rule  string   `json:"rule" yaml:"rule" toml:"rule"`
+	Flag  string   `json:"flag,omitempty" yaml:"flag,omitempty" toml:"flag,omitempty"`
+	Flags []string `json:"flags" yaml:"flags" toml:"flags"`
+	Value string   `json:"value,omitempty" yaml:"value,omitempty" toml:"value,omitempty"`
+}
+
+// relationInfos serializes the relations declared among fields, nil when there are none.
+func relationInfos(fields []structs.Field) []RelationInfo {
+	var infos []RelationInfo
+	for _, relation := range cli.FieldRelations(fields) {
+		infos = append(infos, RelationInfo(relation))
+	}
+	return infos
+}
+
+// SchemaCondition is a JSON Schema subschema over which properties are present (and what they hold),
+// used to express the relation rules: `dependencies` values, `oneOf` branches, and `if`/`then` pairs.
+type SchemaCondition struct {
+	Required   []string               `json:"required,omitempty"`
+	Properties map[string]SchemaConst `json:"properties,omitempty"`
+	Not        *SchemaCondition       `json:"not,omitempty"`
+	AnyOf      []SchemaCondition      `json:"anyOf,omitempty"`
+	OneOf      []SchemaCondition      `json:"oneOf,omitempty"`
+	If         *SchemaCondition       `json:"if,omitempty"`
+	Then       *SchemaCondition       `json:"then,omitempty"`
+}
+
+// SchemaConst pins a property to one value.
+type SchemaConst struct {
+	Const string `json:"const"`
+}
+
+// applyRelationSchema adds the relation rules declared among fields to schema: requires and exclusive
+// rules as `dependencies` of the declaring flag, a one-required group as `oneOf` (further groups, and
+// required-if rules as `if`/`then` pairs, under `allOf`).
+func applyRelationSchema(schema *CommandSchema, fields []structs.Field) {
+	depend := func(flag string, apply func(*SchemaCondition)) {
+		if schema.Dependencies == nil {
+			schema.Dependencies = make(map[string]SchemaCondition)
+		}
+		cond := schema.Dependencies[flag]
+		apply(&cond)
+		schema.Dependencies[flag] = cond
+	}
+
+	for _, relation := range cli.FieldRelations(fields) {
+		switch relation.Rule {
+		case cli.RuleRequires:
+			depend(relation.Flag, func(cond *SchemaCondition) {
+				cond.Required = append(cond.Required, relation.Flags...)
+			})
+		case cli.RuleExclusive:
+			for _, flag := range relation.Flags {
+				others := without(relation.Flags, flag)
+				if len(others) == 0 {
+					continue
+				}
+				depend(flag, func(cond *SchemaCondition) {
+					if cond.Not == nil {
+						cond.Not = &SchemaCondition{}
+					}
+					for _, other := range others {
+						cond.Not.AnyOf = append(cond.Not.AnyOf, SchemaCondition{Required: []string{other}})
+					}
+				})
+			}
+		case cli.RuleOneRequired:
+			branches := make([]SchemaCondition, 0, len(relation.Flags))
+			for _, flag := range relation.Flags {
+				branches = append(branches, SchemaCondition{Required: []string{flag}})
+			}
+			if schema.OneOf == nil {
+				schema.OneOf = branches
+			} else {
+				schema.AllOf = append(schema.AllOf, SchemaCondition{OneOf: branches})
+			}
+		case cli.RuleRequiredIf:
+			when := &SchemaCondition{Required: []string{relation.Flags[0]}}
+			if relation.Value != "" {
+				when.Properties = map[string]SchemaConst{relation.Flags[0]: {Const: relation.Value}}
+			}
+			schema.AllOf = append(schema.AllOf, SchemaCondition{If: when, Then: &SchemaCondition{Required: []string{relation.Flag}}})
+		}
+	}
+}
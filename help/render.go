@@ -1,6 +1,7 @@
 package help
 
 import (
+	"io"
 	"os"
 	"strings"
 )
@@ -17,24 +18,29 @@ const (
 	ansiDivider   = "\033[38;5;238m"
 )
 
-func isTTY() bool {
-	fi, err := os.Stdout.Stat()
+// isTTY reports whether w is a terminal; a writer other than an *os.File (a buffer, an App.IO writer) never is.
+func isTTY(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
 	if err != nil {
 		return false
 	}
 	return fi.Mode()&os.ModeCharDevice != 0
 }
 
-// renderMarkdown dispatches to the right renderer based on format.
-// "md" returns raw markdown, "plain" strips formatting, "pretty" adds ANSI colors.
-func renderMarkdown(text string, format string) string {
+// renderMarkdown dispatches to the right renderer based on format, for text to be written to w.
+// "md" returns raw markdown, "plain" strips formatting, "pretty" adds ANSI colors when w is a terminal.
+func renderMarkdown(w io.Writer, text string, format string) string {
 	if format == "md" {
 		return text
 	}
 	if format == "plain" {
 		return stripMarkdown(text)
 	}
-	if !isTTY() {
+	if !isTTY(w) {
 		return text
 	}
 	return prettyMarkdown(text)
@@ -1,6 +1,7 @@
 package help
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 )
@@ -124,14 +125,20 @@ func Test_resolveFormat(t *testing.T) {
 func Test_renderMarkdown(t *testing.T) {
 	md := "# Title\n**bold**"
 
-	if got := renderMarkdown(md, "md"); got != md {
+	var buf bytes.Buffer
+
+	if got := renderMarkdown(&buf, md, "md"); got != md {
 		t.Fatalf("md format should return raw markdown, got %q", got)
 	}
 
-	plain := renderMarkdown(md, "plain")
+	plain := renderMarkdown(&buf, md, "plain")
 	if strings.Contains(plain, "**") || strings.Contains(plain, "#") {
 		t.Fatalf("plain format should strip markers, got %q", plain)
 	}
+
+	if got := renderMarkdown(&buf, md, "pretty"); strings.Contains(got, "\033[") {
+		t.Fatalf("pretty format should not color output bound for a buffer, got %q", got)
+	}
 }
 
 func Test_visibleWidth(t *testing.T) {
@@ -2,6 +2,8 @@ package help
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/toaweme/structs"
@@ -18,6 +20,9 @@ func fieldRawValue(field structs.Field) string {
 		return ""
 	}
 	s := fmt.Sprintf("%v", v.Interface())
+	if v.Kind() == reflect.Map {
+		s = mapText(v)
+	}
 	if s == "" {
 		return ""
 	}
@@ -27,6 +32,17 @@ func fieldRawValue(field structs.Field) string {
 	return s
 }
 
+// mapText writes a map value as the "key=value" pairs its flag takes, sorted by key and comma-joined.
+func mapText(v reflect.Value) string {
+	pairs := make([]string, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", iter.Key().Interface(), iter.Value().Interface()))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
 // valueText is the resolved value as shown in help: secrets masked and path-like
 // values shortened to their last segments, with no surrounding brackets or quotes.
 // Returns "" for an unset value.
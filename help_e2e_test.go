@@ -1,6 +1,8 @@
 package cli_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -362,6 +364,53 @@ func Test_E2E_Help_FormatJSON_Detail(t *testing.T) {
 	assertTrue(t, fileFlag, "should have file flag")
 }
 
+// Test_E2E_Main_ErrorFormat: Main reports a failed run through the help command, so a validation
+// failure follows --help-format: a JSON object naming each failed field, or plain text lines.
+func Test_E2E_Main_ErrorFormat(t *testing.T) {
+	run := func(args ...string) string {
+		t.Helper()
+		var stderr bytes.Buffer
+		cmd := exec.Command("go", append([]string{"run", "./examples/greet"}, args...)...)
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		if err == nil {
+			t.Fatalf("expected %v to fail", args)
+		}
+		// go run exits 1 and reports the program's own status on stderr
+		assertContains(t, stderr.String(), "exit status 2", "a validation failure is a usage error")
+		return stderr.String()
+	}
+
+	t.Run("json", func(t *testing.T) {
+		out := run("greet", "--help-format=json")
+		var info struct {
+			Error  string `json:"error"`
+			Fields []struct {
+				Field    string `json:"field"`
+				Position *int   `json:"position"`
+				Env      string `json:"env"`
+				Rule     string `json:"rule"`
+			} `json:"fields"`
+		}
+		if err := json.NewDecoder(strings.NewReader(out)).Decode(&info); err != nil {
+			t.Fatalf("expected a JSON error on stderr, got %q: %v", out, err)
+		}
+		assertContains(t, info.Error, "validation failed")
+		assertLen(t, info.Fields, 1)
+		field := info.Fields[0]
+		assertEqual(t, "Name", field.Field)
+		assertEqual(t, "GREET_NAME", field.Env)
+		assertEqual(t, "required", field.Rule)
+		assertNotNil(t, field.Position)
+		assertEqual(t, 0, *field.Position)
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		out := run("greet")
+		assertContains(t, out, "error: validation failed\n  argument 1 ($GREET_NAME) is required\n")
+	})
+}
+
 func Test_E2E_Help_FormatJSONSchema_Detail(t *testing.T) {
 	out := runExample(t, "full", "--help", "--help-format=jsonschema")
 
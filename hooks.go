@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Hook runs around a command's Run: cmd is the matched command, whose Options() already hold the
+// resolved inputs (defaults, config, env, and flags merged and validated). A PreRun hook that returns
+// an error aborts the run before Run is called.
+type Hook func(ctx context.Context, cmd Command[any]) error
+
+// ErrorHook receives the error a command's Run returned and returns the error to surface in its place:
+// the same one, a wrapped or translated one, or nil to swallow it. Hooks chain, each seeing the error
+// the previous one returned; a nil result stops the chain.
+type ErrorHook func(ctx context.Context, cmd Command[any], err error) error
+
+// hookSet is one registration scope's hooks: an App's, or one half (local or persistent) of a command's.
+type hookSet struct {
+	pre     []Hook
+	post    []Hook
+	onError []ErrorHook
+}
+
+// commandHooks holds a command's own hooks (local, run only for that command) and the persistent ones
+// every descendant reached through Add inherits.
+type commandHooks struct {
+	local      hookSet
+	persistent hookSet
+}
+
+// hookProvider is satisfied by every command embedding BaseCommand, so the App can collect hooks
+// without widening the Command interface.
+type hookProvider interface {
+	hooks() *commandHooks
+}
+
+// PreRun registers hooks run before this command's Run (after its inputs are resolved), and returns the app for chaining.
+// App hooks apply to every command.
+func (c *app) PreRun(hooks ...Hook) App {
+	c.appHooks.pre = append(c.appHooks.pre, hooks...)
+
+	return c
+}
+
+// PostRun registers hooks run after a command's Run succeeds, and returns the app for chaining.
+func (c *app) PostRun(hooks ...Hook) App {
+	c.appHooks.post = append(c.appHooks.post, hooks...)
+
+	return c
+}
+
+// OnError registers hooks that receive a command's Run error, and returns the app for chaining.
+func (c *app) OnError(hooks ...ErrorHook) App {
+	c.appHooks.onError = append(c.appHooks.onError, hooks...)
+
+	return c
+}
+
+// commandChain resolves the matched command names (e.g. ["db", "migrate"]) back to the commands
+// themselves, root first, so hooks can be collected from every ancestor.
+func (c *app) commandChain(names []string) []Command[any] {
+	chain := make([]Command[any], 0, len(names))
+	commands := c.commands
+	for _, name := range names {
+		cmd := c.matchCommandByName(name, commands)
+		if cmd == nil {
+			break
+		}
+		chain = append(chain, cmd)
+		commands = cmd.Commands()
+	}
+
+	return chain
+}
+
+// collectHooks flattens the hooks that apply to the last command in chain, in run order.
+// PreRun: app, then each ancestor's persistent hooks root first, then the command's persistent and local hooks.
+// PostRun and OnError unwind in the reverse order, innermost scope first.
+func (c *app) collectHooks(chain []Command[any]) hookSet {
+	scopes := []hookSet{c.appHooks}
+	for i, cmd := range chain {
+		provider, ok := cmd.(hookProvider)
+		if !ok {
+			continue
+		}
+		h := provider.hooks()
+		scopes = append(scopes, h.persistent)
+		if i == len(chain)-1 {
+			scopes = append(scopes, h.local)
+		}
+	}
+
+	var set hookSet
+	for _, scope := range scopes {
+		set.pre = append(set.pre, scope.pre...)
+	}
+	for i := len(scopes) - 1; i >= 0; i-- {
+		set.post = append(set.post, scopes[i].post...)
+		set.onError = append(set.onError, scopes[i].onError...)
+	}
+
+	return set
+}
+
+// execute runs the last command in chain wrapped in its hooks. ErrDisplaySubCommands bypasses
+// the error hooks (it is a request for help, not a failure) and is returned as is for the caller to handle.
+func (c *app) execute(ctx context.Context, chain []Command[any], unknowns Unknowns) error {
+	command := chain[len(chain)-1]
+	hooks := c.collectHooks(chain)
+
+	for _, hook := range hooks.pre {
+		if err := hook(ctx, command); err != nil {
+			return fmt.Errorf("pre-run hook failed: %w", err)
+		}
+	}
+
+	err := runCommand(ctx, c.stdio(), command, *c.globalFlags, unknowns)
+	if err != nil {
+		if errors.Is(err, ErrDisplaySubCommands) {
+			return err
+		}
+		for _, hook := range hooks.onError {
+			if err = hook(ctx, command, err); err == nil {
+				break
+			}
+		}
+		return err
+	}
+
+	for _, hook := range hooks.post {
+		if err := hook(ctx, command); err != nil {
+			return fmt.Errorf("post-run hook failed: %w", err)
+		}
+	}
+
+	return nil
+}
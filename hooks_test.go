@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type hookConfig struct {
+	Name string `arg:"name" default:"anon"`
+}
+
+// hookCommand records its run into a shared trace, and fails with err when set.
+type hookCommand struct {
+	BaseCommand[hookConfig]
+	trace *[]string
+	err   error
+}
+
+var _ Command[hookConfig] = (*hookCommand)(nil)
+
+func (c *hookCommand) Help() string { return "hook" }
+func (c *hookCommand) Run(_ GlobalFlags, _ Unknowns) error {
+	*c.trace = append(*c.trace, "run:"+c.Name(""))
+	return c.err
+}
+
+func traceHook(trace *[]string, label string) Hook {
+	return func(_ context.Context, cmd Command[any]) error {
+		*trace = append(*trace, label+":"+cmd.Name(""))
+		return nil
+	}
+}
+
+func Test_Hooks_Order(t *testing.T) {
+	var trace []string
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.PreRun(traceHook(&trace, "app-pre")).PostRun(traceHook(&trace, "app-post"))
+
+	db := &hookCommand{BaseCommand: NewBaseCommand[hookConfig](), trace: &trace}
+	db.PersistentPreRun(traceHook(&trace, "db-persistent-pre"))
+	db.PersistentPostRun(traceHook(&trace, "db-persistent-post"))
+	db.PreRun(traceHook(&trace, "db-pre"))
+
+	migrate := &hookCommand{BaseCommand: NewBaseCommand[hookConfig](), trace: &trace}
+	migrate.PreRun(traceHook(&trace, "migrate-pre"))
+	migrate.PostRun(traceHook(&trace, "migrate-post"))
+
+	app.Add("db", db).Add("migrate", migrate)
+
+	assertNoError(t, app.Run([]string{"db", "migrate"}))
+	assertEqual(t, []string{
+		"app-pre:migrate",
+		"db-persistent-pre:migrate",
+		"migrate-pre:migrate",
+		"run:migrate",
+		"migrate-post:migrate",
+		"db-persistent-post:migrate",
+		"app-post:migrate",
+	}, trace)
+}
+
+func Test_Hooks_SeeResolvedInputs(t *testing.T) {
+	var seen string
+	app := newTestApp(Config{}, GlobalFlags{})
+	var trace []string
+	cmd := &hookCommand{BaseCommand: NewBaseCommand[hookConfig](), trace: &trace}
+	cmd.PreRun(func(_ context.Context, cmd Command[any]) error {
+		seen = cmd.Options().(*hookConfig).Name
+		return nil
+	})
+	app.Add("greet", cmd)
+
+	assertNoError(t, app.Run([]string{"greet"}))
+	assertEqual(t, "anon", seen)
+}
+
+func Test_Hooks_PreRunErrorAborts(t *testing.T) {
+	var trace []string
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.PreRun(func(_ context.Context, _ Command[any]) error { return errors.New("not logged in") })
+	app.Add("deploy", &hookCommand{BaseCommand: NewBaseCommand[hookConfig](), trace: &trace})
+
+	err := app.Run([]string{"deploy"})
+	assertError(t, err)
+	assertContains(t, err.Error(), "not logged in")
+	assertLen(t, trace, 0)
+}
+
+func Test_Hooks_OnError(t *testing.T) {
+	boom := errors.New("boom")
+
+	t.Run("receives the run error and can translate it", func(t *testing.T) {
+		var trace []string
+		translated := errors.New("translated")
+		app := newTestApp(Config{}, GlobalFlags{})
+		var got error
+		app.OnError(func(_ context.Context, _ Command[any], err error) error {
+			got = err
+			return translated
+		})
+		app.PostRun(traceHook(&trace, "post"))
+		app.Add("deploy", &hookCommand{BaseCommand: NewBaseCommand[hookConfig](), trace: &trace, err: boom})
+
+		err := app.Run([]string{"deploy"})
+		assertErrorIs(t, got, boom)
+		assertErrorIs(t, err, translated)
+		assertEqual(t, []string{"run:deploy"}, trace, "post-run hooks are skipped on failure")
+	})
+
+	t.Run("nil swallows the error", func(t *testing.T) {
+		var trace []string
+		app := newTestApp(Config{}, GlobalFlags{})
+		parent := &hookCommand{BaseCommand: NewBaseCommand[hookConfig](), trace: &trace}
+		parent.PersistentOnError(func(_ context.Context, _ Command[any], _ error) error { return nil })
+		app.Add("db", parent).Add("seed", &hookCommand{BaseCommand: NewBaseCommand[hookConfig](), trace: &trace, err: boom})
+
+		assertNoError(t, app.Run([]string{"db", "seed"}))
+	})
+}
+
+func Test_Hooks_LocalNotInherited(t *testing.T) {
+	var trace []string
+	app := newTestApp(Config{}, GlobalFlags{})
+	db := &hookCommand{BaseCommand: NewBaseCommand[hookConfig](), trace: &trace}
+	db.PreRun(traceHook(&trace, "db-pre"))
+	app.Add("db", db).Add("seed", &hookCommand{BaseCommand: NewBaseCommand[hookConfig](), trace: &trace})
+
+	assertNoError(t, app.Run([]string{"db", "seed"}))
+	assertEqual(t, []string{"run:seed"}, trace)
+}
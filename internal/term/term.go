@@ -0,0 +1,31 @@
+// Package term is the terminal handling shared by the prompter, which turns echo off for a secret
+// answer, and the shell's line editor, which reads keys in raw mode while a line is edited.
+package term
+
+import (
+	"io"
+	"os"
+)
+
+// State is a terminal's mode, saved by DisableEcho or MakeRaw to be put back with Restore.
+type State struct {
+	state
+}
+
+// IsTerminal reports whether r is a terminal (a character device) rather than a pipe or file.
+// The null device is a character device too, and is what CI and `go test` hand a process as stdin.
+func IsTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(fi, null) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,62 @@
+package term
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/toaweme/cli/internal/termtest"
+)
+
+func Test_IsTerminal(t *testing.T) {
+	_, pts := termtest.OpenPTY(t)
+	null, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer null.Close()
+
+	if !IsTerminal(pts) {
+		t.Fatal("expected a pseudo-terminal to be a terminal")
+	}
+	if IsTerminal(null) || IsTerminal(strings.NewReader("")) || IsTerminal(&bytes.Buffer{}) {
+		t.Fatal("expected the null device and readers not to be terminals")
+	}
+}
+
+func Test_DisableEcho(t *testing.T) {
+	_, pts := termtest.OpenPTY(t)
+
+	state, err := DisableEcho(pts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if termtest.Echo(t, pts) || !termtest.Canonical(t, pts) {
+		t.Fatal("expected echo off and line editing kept")
+	}
+	if err := Restore(pts, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !termtest.Echo(t, pts) {
+		t.Fatal("expected echo back on")
+	}
+}
+
+func Test_MakeRaw(t *testing.T) {
+	_, pts := termtest.OpenPTY(t)
+
+	state, err := MakeRaw(pts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if termtest.Echo(t, pts) || termtest.Canonical(t, pts) {
+		t.Fatal("expected echo and line editing off")
+	}
+	if err := Restore(pts, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !termtest.Echo(t, pts) || !termtest.Canonical(t, pts) {
+		t.Fatal("expected the previous mode back")
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package term
+
+import (
+	"errors"
+	"os"
+)
+
+type state struct{}
+
+// DisableEcho is not supported on this platform: the answer is echoed.
+func DisableEcho(*os.File) (*State, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// MakeRaw is not supported on this platform: lines are read whole.
+func MakeRaw(*os.File) (*State, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// Restore is not supported on this platform.
+func Restore(*os.File, *State) error {
+	return errors.ErrUnsupported
+}
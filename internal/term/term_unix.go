@@ -0,0 +1,74 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package term
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+type state struct {
+	termios syscall.Termios
+}
+
+// DisableEcho turns off echo on the terminal f, keeping line editing, and returns the mode to restore.
+func DisableEcho(f *os.File) (*State, error) {
+	termios, err := getTermios(f)
+	if err != nil {
+		return nil, err
+	}
+	saved := &State{state{termios: termios}}
+
+	termios.Lflag &^= syscall.ECHO
+	if err := setTermios(f, &termios); err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// MakeRaw puts the terminal f into raw mode, as cfmakeraw(3) does: keys arrive one by one, unechoed,
+// and Ctrl-C is read as a key rather than sent as SIGINT. It returns the mode to restore.
+func MakeRaw(f *os.File) (*State, error) {
+	termios, err := getTermios(f)
+	if err != nil {
+		return nil, err
+	}
+	saved := &State{state{termios: termios}}
+
+	termios.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	termios.Oflag &^= syscall.OPOST
+	termios.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	termios.Cflag &^= syscall.CSIZE | syscall.PARENB
+	termios.Cflag |= syscall.CS8
+	termios.Cc[syscall.VMIN] = 1
+	termios.Cc[syscall.VTIME] = 0
+	if err := setTermios(f, &termios); err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// Restore puts the terminal f back into the mode s saved.
+func Restore(f *os.File, s *State) error {
+	return setTermios(f, &s.termios)
+}
+
+func getTermios(f *os.File) (syscall.Termios, error) {
+	var termios syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), ioctlGetTermios, uintptr(unsafe.Pointer(&termios))); errno != 0 {
+		return termios, errno
+	}
+
+	return termios, nil
+}
+
+func setTermios(f *os.File, termios *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), ioctlSetTermios, uintptr(unsafe.Pointer(termios))); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package termtest
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// OpenPTY opens a pseudo-terminal, returning its controlling side and the terminal a program reads from.
+// Both are closed when the test ends; the test is skipped where no pseudo-terminal can be opened.
+func OpenPTY(t *testing.T) (*os.File, *os.File) {
+	t.Helper()
+	ptm, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminal: %v", err)
+	}
+	t.Cleanup(func() { _ = ptm.Close() })
+
+	var unlock int32
+	if err := ioctl(ptm, syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		t.Skipf("failed to unlock the pseudo-terminal: %v", err)
+	}
+	var n uint32
+	if err := ioctl(ptm, syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); err != nil {
+		t.Skipf("failed to name the pseudo-terminal: %v", err)
+	}
+	pts, err := os.OpenFile("/dev/pts/"+strconv.Itoa(int(n)), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("failed to open the pseudo-terminal: %v", err)
+	}
+	t.Cleanup(func() { _ = pts.Close() })
+
+	return ptm, pts
+}
+
+// Echo reports whether the terminal f echoes input.
+func Echo(t *testing.T, f *os.File) bool {
+	t.Helper()
+	return lflag(t, f)&syscall.ECHO != 0
+}
+
+// Canonical reports whether the terminal f reads input a line at a time, as it does outside raw mode.
+func Canonical(t *testing.T, f *os.File) bool {
+	t.Helper()
+	return lflag(t, f)&syscall.ICANON != 0
+}
+
+func lflag(t *testing.T, f *os.File) uint32 {
+	t.Helper()
+	var termios syscall.Termios
+	if err := ioctl(f, syscall.TCGETS, uintptr(unsafe.Pointer(&termios))); err != nil {
+		t.Fatalf("failed to read the terminal mode: %v", err)
+	}
+
+	return termios.Lflag
+}
+
+func ioctl(f *os.File, req, arg uintptr) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, arg); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package termtest opens pseudo-terminals for tests of the terminal handling in internal/term.
+package termtest
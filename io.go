@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Environment is the source of environment variables an App reads: env-bound fields, deprecated
+// env forwarding, and the plugin environment. .env loading (App.DotEnv) writes into it.
+type Environment interface {
+	// Environ returns the variables as KEY=value pairs, like os.Environ.
+	Environ() []string
+	// LookupEnv returns the value of key and whether it is set, like os.LookupEnv.
+	LookupEnv(key string) (string, bool)
+	// Setenv sets key to value, like os.Setenv.
+	Setenv(key, value string) error
+}
+
+// osEnv is the process environment.
+type osEnv struct{}
+
+func (osEnv) Environ() []string                   { return os.Environ() }
+func (osEnv) LookupEnv(key string) (string, bool) { return os.LookupEnv(key) }
+func (osEnv) Setenv(key, value string) error      { return os.Setenv(key, value) }
+
+// OSEnv returns the process environment, the App's default Environment.
+func OSEnv() Environment {
+	return osEnv{}
+}
+
+// mapEnv is an in-memory Environment, safe for concurrent use.
+type mapEnv struct {
+	mu   sync.RWMutex
+	vars map[string]string
+}
+
+// MapEnv returns an in-memory Environment seeded with vars, isolated from the process environment:
+// tests give each App its own, so they no longer need t.Setenv and can run in parallel.
+func MapEnv(vars map[string]string) Environment {
+	env := &mapEnv{vars: make(map[string]string, len(vars))}
+	for k, v := range vars {
+		env.vars[k] = v
+	}
+
+	return env
+}
+
+func (e *mapEnv) Environ() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	pairs := make([]string, 0, len(e.vars))
+	for k, v := range e.vars {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return pairs
+}
+
+func (e *mapEnv) LookupEnv(key string) (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	v, ok := e.vars[key]
+	return v, ok
+}
+
+func (e *mapEnv) Setenv(key, value string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.vars[key] = value
+	return nil
+}
+
+// IO is the standard streams and environment an App and its commands use. A nil field falls back
+// to the process's own (os.Stdin, os.Stdout, os.Stderr, OSEnv), resolved when used, not when set.
+type IO struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+	Env Environment
+
+	// reader is the app's buffered reader of In, nil outside of an App.
+	reader *bufio.Reader
+}
+
+// Reader returns In buffered, for reading it a line or a rune at a time. Under an App it is the one
+// reader the app keeps for In, so a shell reading lines and a prompt asking for a missing input never
+// lose each other's read-ahead. Outside of an App it is a new reader on each call.
+func (s IO) Reader() *bufio.Reader {
+	if s.reader != nil {
+		return s.reader
+	}
+
+	return bufio.NewReader(s.withDefaults().In)
+}
+
+// withDefaults fills every unset field from the process.
+func (s IO) withDefaults() IO {
+	if s.In == nil {
+		s.In = os.Stdin
+	}
+	if s.Out == nil {
+		s.Out = os.Stdout
+	}
+	if s.Err == nil {
+		s.Err = os.Stderr
+	}
+	if s.Env == nil {
+		s.Env = OSEnv()
+	}
+
+	return s
+}
+
+// ioBinder is satisfied by every command embedding BaseCommand, so the App can hand it the run's IO
+// without widening the Command interface.
+type ioBinder interface {
+	bindIO(streams IO)
+}
+
+// IO sets the streams and environment the app's built-ins and commands use, and returns the app for chaining.
+func (c *app) IO(streams IO) App {
+	c.inputMu.Lock()
+	c.streams = streams
+	c.input = nil
+	c.inputMu.Unlock()
+
+	return c
+}
+
+// DotEnv registers .env files loaded into the app's environment at the start of every run,
+// and returns the app for chaining.
+func (c *app) DotEnv(paths ...string) App {
+	c.dotenvs = append(c.dotenvs, paths...)
+	if len(c.dotenvs) == 0 {
+		c.dotenvs = []string{".env"}
+	}
+
+	return c
+}
+
+// stdio returns the app's IO, unset fields filled from the process, with the app's reader of In.
+func (c *app) stdio() IO {
+	c.inputMu.Lock()
+	defer c.inputMu.Unlock()
+
+	s := c.streams.withDefaults()
+	// an unset In is the process's stdin as of now, so a reader of an earlier one is replaced
+	if c.input == nil || (c.streams.In == nil && c.inputOf != s.In) {
+		c.input = bufio.NewReader(s.In)
+		c.inputOf = s.In
+	}
+	s.reader = c.input
+
+	return s
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type ioConfig struct {
+	Region string `arg:"region" env:"IO_TEST_REGION"`
+	Old    string `arg:"old" env:"IO_TEST_OLD" deprecated:"renamed" replacement:"region"`
+}
+
+type ioCommand struct {
+	BaseCommand[ioConfig]
+}
+
+func (c *ioCommand) Help() string { return "print the region" }
+func (c *ioCommand) Run(_ GlobalFlags, _ Unknowns) error {
+	_, err := c.IO().Out.Write([]byte("region=" + c.Inputs.Region + "\n"))
+	return err
+}
+
+func newIOApp(streams IO) App {
+	app := NewApp(Config{Name: "app", Version: "1.0.0"}, GlobalFlags{}).IO(streams)
+	app.Add("help", &recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+	app.Add("region", &ioCommand{BaseCommand: NewBaseCommand[ioConfig]()})
+	return app
+}
+
+func Test_IO_CommandsAndBuiltIns(t *testing.T) {
+	t.Parallel()
+
+	var out, errOut bytes.Buffer
+	env := MapEnv(map[string]string{"IO_TEST_OLD": "eu"})
+	app := newIOApp(IO{Out: &out, Err: &errOut, Env: env})
+
+	assertNoError(t, app.Run([]string{"region"}))
+	assertEqual(t, "region=eu\n", out.String(), "the command reads the app env and writes to the app stdout")
+	assertContains(t, errOut.String(), "warning: env 'IO_TEST_OLD' is deprecated", "warnings go to the app stderr")
+
+	out.Reset()
+	assertErrorIs(t, app.Run([]string{"--version"}), ErrShowingVersion)
+	assertEqual(t, "app 1.0.0\n", out.String())
+
+	out.Reset()
+	assertNoError(t, app.Run([]string{"__complete", "reg"}))
+	assertContains(t, out.String(), "region\tprint the region")
+}
+
+func Test_IO_MapEnvIsolated(t *testing.T) {
+	t.Setenv("IO_TEST_REGION", "process")
+
+	var out bytes.Buffer
+	app := newIOApp(IO{Out: &out, Env: MapEnv(nil)})
+	assertNoError(t, app.Run([]string{"region"}))
+	assertEqual(t, "region=\n", out.String(), "the process env is not consulted")
+}
+
+func Test_IO_Reader(t *testing.T) {
+	a := newTestApp(Config{Name: "app"}, GlobalFlags{})
+	a.IO(IO{In: strings.NewReader("one\ntwo\n")})
+
+	first := a.stdio().Reader()
+	assertEqual(t, true, first == a.stdio().Reader(), "every run reads In through the same reader")
+	line, err := first.ReadString('\n')
+	assertNoError(t, err)
+	assertEqual(t, "one\n", line)
+	line, err = a.stdio().Reader().ReadString('\n')
+	assertNoError(t, err)
+	assertEqual(t, "two\n", line, "no read-ahead is lost between readers")
+
+	a.IO(IO{In: strings.NewReader("three\n")})
+	line, err = a.stdio().Reader().ReadString('\n')
+	assertNoError(t, err)
+	assertEqual(t, "three\n", line, "a new In gets a new reader")
+
+	streams := IO{In: strings.NewReader("four\n")}
+	line, err = streams.Reader().ReadString('\n')
+	assertNoError(t, err)
+	assertEqual(t, "four\n", line, "outside of an App the reader wraps In")
+}
+
+func Test_IO_DotEnv(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("IO_TEST_REGION=from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	env := MapEnv(nil)
+	app := newIOApp(IO{Out: &out, Env: env}).DotEnv(path)
+	assertNoError(t, app.Run([]string{"region"}))
+	assertEqual(t, "region=from-file\n", out.String())
+
+	_, inProcess := os.LookupEnv("IO_TEST_REGION")
+	assertEqual(t, false, inProcess, ".env loads into the app env, not the process")
+
+	out.Reset()
+	assertNoError(t, env.Setenv("IO_TEST_REGION", "set"))
+	assertNoError(t, app.Run([]string{"region"}))
+	assertEqual(t, "region=set\n", out.String(), ".env never overrides a set variable")
+}
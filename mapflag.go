@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/toaweme/structs"
+)
+
+// isMapType reports whether fields of type t are maps decoded by decodeMap: string keys and values
+// of a scalar kind or a text type (see Value), such as map[string]string or map[string]int.
+func isMapType(t reflect.Type) bool {
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return false
+	}
+	switch t.Elem().Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return isTextType(t.Elem())
+}
+
+// FieldMap reports whether field is a map flag ("--label env=prod"), which help shows as an object.
+func FieldMap(field structs.Field) bool {
+	return field.Value.IsValid() && isMapType(field.Value.Type())
+}
+
+// decodeMap sets v, a map isMapType accepts, to the entries raw holds: a config object, or the
+// "key=value" text of a flag or env var, each split on sep ("env=prod,team=core"), a repeated flag
+// giving one such text per occurrence. The whole map is replaced, so a layer never merges into another.
+func decodeMap(v reflect.Value, raw any, sep string) error {
+	out := reflect.MakeMap(v.Type())
+	put := func(key string, text string) error {
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := decodeElem(elem, text); err != nil {
+			return fmt.Errorf("entry %q: %w", key, err)
+		}
+		out.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+		return nil
+	}
+	putText := func(text string) error {
+		for _, entry := range strings.Split(text, sep) {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(entry, "=")
+			if !ok || key == "" {
+				return fmt.Errorf("entry %q: want key=value", entry)
+			}
+			if err := put(key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch x := raw.(type) {
+	case structs.MultiValue:
+		for _, text := range x {
+			if err := putText(text); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range x {
+			if err := putText(fmt.Sprint(item)); err != nil {
+				return err
+			}
+		}
+	case string:
+		if err := putText(x); err != nil {
+			return err
+		}
+	default:
+		rv := reflect.ValueOf(raw)
+		if rv.Kind() != reflect.Map {
+			return fmt.Errorf("want key=value pairs, got %T", raw)
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			if err := put(fmt.Sprint(iter.Key().Interface()), fmt.Sprint(iter.Value().Interface())); err != nil {
+				return err
+			}
+		}
+	}
+	v.Set(out)
+
+	return nil
+}
+
+// decodeElem parses text into v, a map value of a type isMapType accepts.
+func decodeElem(v reflect.Value, text string) error {
+	if isTextType(v.Type()) {
+		return decodeText(v, text)
+	}
+	text = strings.TrimSpace(text)
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// fieldSep is the separator splitting one value of a slice or map field into several, from its sep tag.
+func fieldSep(field structs.Field) string {
+	if sep := field.Tags["sep"]; sep != "" {
+		return sep
+	}
+
+	return ","
+}
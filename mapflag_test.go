@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/toaweme/cli/config"
+)
+
+type mapsConfig struct {
+	Labels  map[string]string `arg:"label" short:"l" json:"labels" env:"MAPS_LABELS"`
+	Limits  map[string]int    `arg:"limit" json:"limits" sep:";"`
+	Headers map[string]string `arg:"header" default:"accept=json"`
+}
+
+type mapsCommand struct {
+	BaseCommand[mapsConfig]
+}
+
+func (c *mapsCommand) Help() string                        { return "deploy" }
+func (c *mapsCommand) Run(_ GlobalFlags, _ Unknowns) error { return nil }
+
+func runMaps(t *testing.T, resolver Resolver, env map[string]string, args ...string) (*mapsCommand, error) {
+	t.Helper()
+	cmd := &mapsCommand{BaseCommand: NewBaseCommand[mapsConfig]()}
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.IO(IO{Env: MapEnv(env)})
+	if resolver != nil {
+		app.Resolve(resolver)
+	}
+	app.Add("help", &recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+	app.Add("deploy", cmd)
+
+	return cmd, app.Run(append([]string{"deploy"}, args...))
+}
+
+func Test_App_MapFlags(t *testing.T) {
+	cmd, err := runMaps(t, nil, nil, "--label", "env=prod", "-l", "team=core", "--limit=cpu=2;mem=512", "--label=tier=web,zone=a")
+	assertNoError(t, err)
+
+	in := cmd.Inputs
+	assertEqual(t, map[string]string{"env": "prod", "team": "core", "tier": "web", "zone": "a"}, in.Labels, "every occurrence and sep-split entry is kept")
+	assertEqual(t, map[string]int{"cpu": 2, "mem": 512}, in.Limits, "values parse to the map's type, split on the sep tag")
+	assertEqual(t, map[string]string{"accept": "json"}, in.Headers, "the default is parsed")
+}
+
+func Test_App_MapFlags_Layers(t *testing.T) {
+	store := fileStore(t, map[string]any{
+		"labels": map[string]any{"env": "staging", "team": "core"},
+		"limits": map[string]any{"cpu": 4},
+	})
+	resolver := config.NewResolver(store, nil)
+
+	cmd, err := runMaps(t, resolver, nil)
+	assertNoError(t, err)
+	assertEqual(t, map[string]string{"env": "staging", "team": "core"}, cmd.Inputs.Labels, "a nested config object fills the map")
+	assertEqual(t, map[string]int{"cpu": 4}, cmd.Inputs.Limits)
+
+	cmd, err = runMaps(t, resolver, map[string]string{"MAPS_LABELS": "env=qa,team=infra"})
+	assertNoError(t, err)
+	assertEqual(t, map[string]string{"env": "qa", "team": "infra"}, cmd.Inputs.Labels, "env beats config")
+
+	cmd, err = runMaps(t, resolver, map[string]string{"MAPS_LABELS": "env=qa"}, "--label", "env=prod")
+	assertNoError(t, err)
+	assertEqual(t, map[string]string{"env": "prod"}, cmd.Inputs.Labels, "a flag replaces the whole map")
+}
+
+func Test_App_MapFlags_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "missing =", args: []string{"--label", "prod"}, want: `--label (-l, $MAPS_LABELS) is not a valid map[string]string (from --label): entry "prod": want key=value`},
+		{name: "bad value", args: []string{"--limit", "cpu=lots"}, want: `is not a valid map[string]int (from --limit): entry "cpu"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runMaps(t, nil, nil, tt.args...)
+			assertErrorIs(t, err, ErrValidationFailed)
+			assertContains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/toaweme/structs"
+)
+
+// negationPrefix turns a bool flag off when put in front of its name: "--no-color" is "--color=false".
+const negationPrefix = "no-"
+
+// tagNegatable controls the "--no-<name>" form of a bool field: "false" refuses it, "true" lists it in
+// help and completion even when the flag defaults to false.
+const tagNegatable = "negatable"
+
+// FieldNegatable reports whether help and completion advertise the "--no-<name>" form of a field:
+// a bool that defaults to true (the only way to turn it off), or one tagged negatable:"true".
+// Every bool flag accepts the form unless tagged negatable:"false"; this only decides where it is shown.
+func FieldNegatable(field structs.Field) bool {
+	if !acceptsNegation(field) {
+		return false
+	}
+	if field.Tags[tagNegatable] == "true" {
+		return true
+	}
+
+	return truthyDefault(field.Tags["default"])
+}
+
+// acceptsNegation reports whether field takes the "--no-<name>" form: any bool not tagged negatable:"false".
+func acceptsNegation(field structs.Field) bool {
+	return field.Type == "bool" && field.Tags[tagNegatable] != "false"
+}
+
+// truthyDefault reports whether a default tag value parses as true.
+func truthyDefault(value string) bool {
+	b, err := strconv.ParseBool(value)
+	return err == nil && b
+}
+
+// negatedFlag resolves name written as "no-<flag>" to the bool flag it turns off, returning the flag
+// name without the prefix, or "" when name is no negation. A field actually named "no-..." wins, so
+// the form never shadows one.
+func negatedFlag(fields []structs.Field, name string) string {
+	flag, ok := strings.CutPrefix(name, negationPrefix)
+	if !ok || flag == "" || matchField(fields, name) != nil {
+		return ""
+	}
+	field := matchField(fields, flag)
+	if field == nil || !acceptsNegation(*field) {
+		return ""
+	}
+
+	return flag
+}
+
+// negatedValue is what "--no-<flag>=value" sets the flag to: the opposite of value, so
+// "--no-color=false" turns color back on. A value that isn't a bool is kept as written for the
+// field's own parsing to reject.
+func negatedValue(value string) any {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return value
+	}
+
+	return !b
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/toaweme/structs"
+)
+
+type negatableConfig struct {
+	Color  bool `arg:"color" default:"true" help:"Colorize output"`
+	Cache  bool `arg:"cache" negatable:"true" help:"Use the cache"`
+	Strict bool `arg:"strict" negatable:"false" help:"Fail on warnings"`
+	Debug  bool `arg:"debug" help:"Log debug output"`
+}
+
+type negatableCommand struct {
+	BaseCommand[negatableConfig]
+}
+
+func (m *negatableCommand) Help() string                        { return "paint" }
+func (m *negatableCommand) Run(_ GlobalFlags, _ Unknowns) error { return nil }
+
+func Test_App_NegatedFlags(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		color bool
+		debug bool
+	}{
+		{name: "default", args: []string{"paint"}, color: true},
+		{name: "negated", args: []string{"paint", "--no-color"}, color: false},
+		{name: "negated then set", args: []string{"paint", "--no-color", "--color"}, color: true},
+		{name: "negated with a value", args: []string{"paint", "--no-color=false"}, color: true},
+		{name: "any bool", args: []string{"paint", "--debug", "--no-debug"}, color: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &negatableCommand{BaseCommand: NewBaseCommand[negatableConfig]()}
+			app := newTestApp(Config{}, GlobalFlags{})
+			app.Add("paint", cmd)
+
+			assertNoError(t, app.Run(tt.args))
+			assertEqual(t, tt.color, cmd.Inputs.Color, "color")
+			assertEqual(t, tt.debug, cmd.Inputs.Debug, "debug")
+		})
+	}
+}
+
+func Test_App_Complete_NegatedFlags(t *testing.T) {
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.Add("paint", &negatableCommand{BaseCommand: NewBaseCommand[negatableConfig]()})
+
+	var got []string
+	for _, c := range app.Complete([]string{"paint", "--no"}) {
+		got = append(got, c.Value)
+	}
+	assertEqual(t, []string{"--no-color", "--no-cache", "--no-input"}, got)
+}
+
+func Test_FieldNegatable(t *testing.T) {
+	cmd := &negatableCommand{BaseCommand: NewBaseCommand[negatableConfig]()}
+	fields, err := structs.GetStructFields(cmd.Options(), nil, structs.DefaultEncodingTags)
+	assertNoError(t, err)
+
+	got := map[string]bool{}
+	for _, field := range fields {
+		got[field.Tags[tagArg]] = FieldNegatable(field)
+	}
+	assertEqual(t, map[string]bool{"color": true, "cache": true, "strict": false, "debug": false}, got)
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/toaweme/structs"
+)
+
+// Persistent is implemented by a parent command whose flags every subcommand beneath it accepts.
+// PersistentOptions returns a pointer to the inherited part of the command's config: its whole
+// options struct (return c.Options()), or one of its struct fields (c.Options(); return &c.Inputs.Conn).
+// When a descendant runs, the App parses those flags alongside the descendant's own and merges them
+// (defaults, config, env, flags) into the parent, where the descendant reads them through Inherited.
+// A descendant's own flag wins a name clash; positional args are never inherited.
+type Persistent interface {
+	PersistentOptions() any
+}
+
+// parentProvider is satisfied by every command embedding BaseCommand (see BaseCommand.Parent).
+type parentProvider interface {
+	Parent() Command[any]
+}
+
+// parentBinder is satisfied by every command embedding BaseCommand, so the App can record the
+// dispatched parent without widening the Command interface.
+type parentBinder interface {
+	bindParent(parent Command[any])
+}
+
+// Inherited returns the persistent options of the nearest ancestor of cmd whose PersistentOptions
+// is a *T, or nil when none is. cmd must embed BaseCommand, which records its parent at dispatch:
+//
+//	dsn := cli.Inherited[DBConn](c).DSN
+func Inherited[T any](cmd Command[any]) *T {
+	for {
+		provider, ok := cmd.(parentProvider)
+		if !ok || provider.Parent() == nil {
+			return nil
+		}
+		cmd = provider.Parent()
+		if p, ok := cmd.(Persistent); ok {
+			if options, ok := p.PersistentOptions().(*T); ok {
+				return options
+			}
+		}
+	}
+}
+
+// PersistentFields returns the flag fields cmd hands down to its subcommands: the fields of its
+// PersistentOptions without positional args. Nil when cmd is not Persistent.
+func PersistentFields(cmd Command[any]) ([]structs.Field, error) {
+	p, ok := cmd.(Persistent)
+	if !ok {
+		return nil, nil
+	}
+	options := p.PersistentOptions()
+	if options == nil {
+		return nil, nil
+	}
+	fields, err := structs.GetStructFields(options, nil, structs.DefaultEncodingTags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get persistent fields of command %q: %w", cmd.Name(""), err)
+	}
+
+	applyEnvNames(fields, envPrefixOf(cmd))
+
+	return slices.DeleteFunc(fields, func(field structs.Field) bool {
+		return isPositional(field.Tags[tagArg])
+	}), nil
+}
+
+// inheritedOptions is one ancestor's persistent options as seen from the dispatched command.
+type inheritedOptions struct {
+	cmd     Command[any]
+	path    string
+	options any
+	fields  []structs.Field
+	flags   map[string]any
+}
+
+// inheritedChain binds each command in chain to its parent and returns the persistent options of the
+// ancestors (every command but the last), nearest first, so the nearest one wins a flag name clash.
+func inheritedChain(chain []Command[any]) ([]*inheritedOptions, error) {
+	var inherited []*inheritedOptions
+	for i, cmd := range chain {
+		if binder, ok := cmd.(parentBinder); ok && i > 0 {
+			binder.bindParent(chain[i-1])
+		}
+		if i == len(chain)-1 {
+			break
+		}
+		fields, err := PersistentFields(cmd)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		path := make([]string, 0, i+1)
+		for _, ancestor := range chain[:i+1] {
+			path = append(path, ancestor.Name(""))
+		}
+		inherited = append(inherited, &inheritedOptions{
+			cmd:     cmd,
+			path:    strings.Join(path, " "),
+			options: cmd.(Persistent).PersistentOptions(),
+			fields:  fields,
+			flags:   map[string]any{},
+		})
+	}
+	slices.Reverse(inherited)
+
+	return inherited, nil
+}
+
+// inheritedFields returns every inherited flag field, nearest ancestor first, for the parser to
+// recognise after the dispatched command's own fields.
+func inheritedFields(inherited []*inheritedOptions) []structs.Field {
+	var fields []structs.Field
+	for _, in := range inherited {
+		fields = append(fields, in.fields...)
+	}
+
+	return fields
+}
+
+// routeInherited moves each parsed flag that is not one of the command's own (commandFields) into
+// the flags of the nearest ancestor declaring it.
+func routeInherited(flags map[string]any, commandFields []structs.Field, inherited []*inheritedOptions) {
+	for key, value := range flags {
+		if matchField(commandFields, key) != nil {
+			continue
+		}
+		for _, in := range inherited {
+			if matchField(in.fields, key) != nil {
+				in.flags[key] = value
+				delete(flags, key)
+				break
+			}
+		}
+	}
+}
+
+// loadInherited merges each ancestor's persistent options from the ordered layers and, unless
+// resolveOnly (the --help-values path), validates them against their rules.
+func (c *app) loadInherited(inherited []*inheritedOptions, resolveOnly bool) error {
+	for _, in := range inherited {
+		if err := c.resolveConfig(in.options, in.cmd.Name(""), in.path, in.flags); err != nil {
+			return err
+		}
+		if resolveOnly {
+			continue
+		}
+
+		validateInputs := c.validationInputs(in.options, in.flags)
+		manager := structs.New(in.options, structs.WithTags(defaultTags...))
+		validationErrs, err := manager.Validate(validateInputs)
+		if err != nil {
+			return fmt.Errorf("failed to validate persistent options of command %q: %w", in.cmd.Name(""), err)
+		}
+		if len(validationErrs) > 0 {
+			return fmt.Errorf("failed to validate command %q: %w", in.cmd.Name(""), newValidationError(in.options, envPrefixOf(in.cmd), validationErrs, validateInputs))
+		}
+	}
+
+	return nil
+}
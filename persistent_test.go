@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type dbConn struct {
+	DSN     string `arg:"dsn" env:"PERSIST_DSN" help:"Database DSN" default:"postgres://localhost"`
+	Timeout int    `arg:"timeout" help:"Connect timeout" rules:"required"`
+}
+
+type dbConfig struct {
+	Conn  dbConn
+	Local string `arg:"local" help:"Not inherited"`
+}
+
+// dbCommand hands down its Conn part only.
+type dbCommand struct {
+	BaseCommand[dbConfig]
+}
+
+var _ Persistent = (*dbCommand)(nil)
+
+func (c *dbCommand) Help() string                        { return "db" }
+func (c *dbCommand) Run(_ GlobalFlags, _ Unknowns) error { return ErrDisplaySubCommands }
+func (c *dbCommand) PersistentOptions() any {
+	c.Options()
+	return &c.Inputs.Conn
+}
+
+type migrateConfig struct {
+	Steps int    `arg:"steps" help:"Steps"`
+	DSN   string `arg:"dsn" help:"Own DSN, shadows the inherited one"`
+}
+
+type migrateCommand struct {
+	BaseCommand[migrateConfig]
+	run func(c *migrateCommand) error
+}
+
+func (c *migrateCommand) Help() string                        { return "migrate" }
+func (c *migrateCommand) Run(_ GlobalFlags, _ Unknowns) error { return c.run(c) }
+
+type seedCommand struct {
+	BaseCommand[MockCommandConfig]
+	got *dbConn
+}
+
+func (c *seedCommand) Help() string { return "seed" }
+func (c *seedCommand) Run(_ GlobalFlags, _ Unknowns) error {
+	*c.got = *Inherited[dbConn](c)
+	return nil
+}
+
+func newPersistentApp(got *dbConn, migrate func(c *migrateCommand) error) App {
+	app := NewApp(Config{Name: "app"}, GlobalFlags{})
+	app.Add("help", NewMockCommand(func() error { return nil }))
+	db := app.Add("db", &dbCommand{BaseCommand: NewBaseCommand[dbConfig]()})
+	db.Add("seed", &seedCommand{BaseCommand: NewBaseCommand[MockCommandConfig](), got: got})
+	db.Add("migrate", &migrateCommand{BaseCommand: NewBaseCommand[migrateConfig](), run: migrate})
+	return app
+}
+
+func Test_Persistent_InheritedFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		wantDSN string
+		wantErr error
+	}{
+		{name: "flag", args: []string{"db", "seed", "--dsn", "mysql://x", "--timeout", "5"}, wantDSN: "mysql://x"},
+		{name: "flag before subcommand args", args: []string{"db", "seed", "--timeout=5", "--beep"}, wantDSN: "postgres://localhost"},
+		{name: "env", args: []string{"db", "seed", "--timeout", "5"}, env: map[string]string{"PERSIST_DSN": "env://dsn"}, wantDSN: "env://dsn"},
+		{name: "rules are enforced", args: []string{"db", "seed"}, wantErr: ErrValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			got := &dbConn{}
+			err := newPersistentApp(got, nil).Run(tt.args)
+			if tt.wantErr != nil {
+				assertErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, tt.wantDSN, got.DSN)
+			assertEqual(t, 5, got.Timeout)
+		})
+	}
+}
+
+func Test_Persistent_OwnFlagWinsClash(t *testing.T) {
+	var own string
+	var inherited dbConn
+	app := newPersistentApp(&dbConn{}, func(c *migrateCommand) error {
+		own = c.Inputs.DSN
+		inherited = *Inherited[dbConn](c)
+		return nil
+	})
+
+	assertNoError(t, app.Run([]string{"db", "migrate", "--dsn", "sqlite://", "--timeout", "5"}))
+	assertEqual(t, "sqlite://", own, "the subcommand's own flag takes the value")
+	assertEqual(t, "postgres://localhost", inherited.DSN, "the shadowed inherited flag keeps its default")
+	assertEqual(t, 5, inherited.Timeout)
+}
+
+func Test_Persistent_NotInheritedFlagIsUnknown(t *testing.T) {
+	var unknowns Unknowns
+	app := NewApp(Config{Name: "app"}, GlobalFlags{})
+	app.Add("help", NewMockCommand(func() error { return nil }))
+	db := app.Add("db", &dbCommand{BaseCommand: NewBaseCommand[dbConfig]()})
+	db.Add("seed", &unknownsRecorder{BaseCommand: NewBaseCommand[MockCommandConfig](), got: &unknowns})
+
+	assertNoError(t, app.Run([]string{"db", "seed", "--timeout", "1", "--local", "x"}))
+	assertEqual(t, "x", unknowns.Options["local"], "only the persistent part is inherited")
+	if _, ok := unknowns.Options["timeout"]; ok {
+		t.Fatalf("expected the inherited flag to be consumed, got %v", unknowns.Options)
+	}
+}
+
+type unknownsRecorder struct {
+	BaseCommand[MockCommandConfig]
+	got *Unknowns
+}
+
+func (c *unknownsRecorder) Help() string { return "recorder" }
+func (c *unknownsRecorder) Run(_ GlobalFlags, unknowns Unknowns) error {
+	*c.got = unknowns
+	return nil
+}
+
+func Test_Persistent_InheritedNilWithoutAncestor(t *testing.T) {
+	cmd := &seedCommand{BaseCommand: NewBaseCommand[MockCommandConfig]()}
+	assertNil(t, Inherited[dbConn](cmd))
+
+	var err error
+	app := NewApp(Config{Name: "app"}, GlobalFlags{})
+	app.Add("help", NewMockCommand(func() error { return nil }))
+	app.Add("plain", NewMockCommand(func() error {
+		if Inherited[dbConn](cmd) != nil {
+			err = errors.New("unexpected inherited options")
+		}
+		return nil
+	}))
+	assertNoError(t, app.Run([]string{"plain"}))
+	assertNoError(t, err)
+}
+
+func Test_Persistent_Completion(t *testing.T) {
+	app := newTestApp(Config{Name: "app"}, GlobalFlags{})
+	db := app.Add("db", &dbCommand{BaseCommand: NewBaseCommand[dbConfig]()})
+	db.Add("seed", &seedCommand{BaseCommand: NewBaseCommand[MockCommandConfig](), got: &dbConn{}})
+
+	out := completeOutput(app, "db", "seed", "--")
+	for _, want := range []string{"--beep", "--dsn", "--timeout", "--help"} {
+		assertContains(t, out, want)
+	}
+	if strings.Contains(out, "--local") {
+		t.Fatalf("expected the non-persistent parent flag to be left out, got:\n%s", out)
+	}
+	if strings.Index(out, "--beep") > strings.Index(out, "--dsn") || strings.Index(out, "--dsn") > strings.Index(out, "--help") {
+		t.Fatalf("expected own, then inherited, then global flags, got:\n%s", out)
+	}
+}
@@ -0,0 +1,394 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables describing the parent app to a plugin it runs (see App.Plugins).
+const (
+	// EnvPluginAppName holds the parent app's Config.Name.
+	EnvPluginAppName = "CLI_APP_NAME"
+	// EnvPluginAppVersion holds the parent app's Config.Version.
+	EnvPluginAppVersion = "CLI_APP_VERSION"
+	// EnvPluginAppExecutable holds the path of the parent app's binary, so a plugin can call back into it.
+	EnvPluginAppExecutable = "CLI_APP_EXECUTABLE"
+	// EnvPluginCommand holds the command name the plugin was invoked as (the <cmd> in <app>-<cmd>).
+	EnvPluginCommand = "CLI_PLUGIN_COMMAND"
+	// EnvPluginCwd holds the --cwd passed to the parent app, when one was.
+	EnvPluginCwd = "CLI_APP_CWD"
+)
+
+// pluginDescribeArg is the handshake argument a plugin answers by printing its help.CommandInfo JSON.
+const pluginDescribeArg = "__describe"
+
+// pluginDescribeTimeout bounds each __describe handshake, so a plugin that ignores it cannot hang help.
+const pluginDescribeTimeout = 2 * time.Second
+
+// pluginInfo is the subset of the help.CommandInfo JSON shape a plugin's __describe output is read into.
+// It is declared here rather than imported because the help package depends on the core.
+type pluginInfo struct {
+	Name        string              `json:"name"`
+	Help        string              `json:"help"`
+	Description string              `json:"description"`
+	Flags       []pluginFlag        `json:"flags"`
+	Examples    [][]string          `json:"examples"`
+	ArgDocs     map[string][]string `json:"argDescriptions"`
+	FlagDocs    map[string][]string `json:"flagDescriptions"`
+	SubCommands []pluginInfo        `json:"subcommands"`
+}
+
+// pluginFlag is the subset of the help.FlagInfo JSON shape a plugin describes its flags with.
+type pluginFlag struct {
+	Name     string `json:"name"`
+	Short    string `json:"short"`
+	Help     string `json:"help"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	Default  string `json:"default"`
+	Env      string `json:"env"`
+}
+
+// pluginCommand is a plugin listed among the app's commands. Its options struct is built from the
+// flags the plugin described, so help, completion, and gendocs render it like a built-in command.
+// It never runs through Run: dispatch execs the plugin binary directly (see runPlugin).
+type pluginCommand struct {
+	BaseCommand[struct{}]
+	path    string
+	info    pluginInfo
+	options any
+}
+
+var _ Command[any] = (*pluginCommand)(nil)
+
+// newPluginCommand builds the command for the plugin at path from its description, subcommands included.
+func newPluginCommand(name, path string, info pluginInfo) *pluginCommand {
+	cmd := &pluginCommand{
+		BaseCommand: NewBaseCommand[struct{}](),
+		path:        path,
+		info:        info,
+		options:     pluginOptions(info.Flags),
+	}
+	cmd.Name(name)
+	for _, sub := range info.SubCommands {
+		if sub.Name == "" {
+			continue
+		}
+		cmd.Add(sub.Name, newPluginCommand(sub.Name, path, sub))
+	}
+
+	return cmd
+}
+
+// Help returns the plugin's one-line summary, or its path when it did not describe itself.
+func (c *pluginCommand) Help() string {
+	if c.info.Help == "" {
+		return "plugin: " + c.path
+	}
+
+	return c.info.Help
+}
+
+// Description returns the plugin's long-form description.
+func (c *pluginCommand) Description() string { return c.info.Description }
+
+// Examples returns the plugin's usage examples.
+func (c *pluginCommand) Examples() [][]string { return c.info.Examples }
+
+// Args returns the plugin's positional-argument descriptions.
+func (c *pluginCommand) Args() map[int][]string {
+	if len(c.info.ArgDocs) == 0 {
+		return nil
+	}
+	docs := make(map[int][]string, len(c.info.ArgDocs))
+	for key, lines := range c.info.ArgDocs {
+		if pos, err := strconv.Atoi(key); err == nil {
+			docs[pos] = lines
+		}
+	}
+
+	return docs
+}
+
+// Flags returns the plugin's flag descriptions.
+func (c *pluginCommand) Flags() map[string][]string { return c.info.FlagDocs }
+
+// Options returns the options struct built from the plugin's described flags.
+func (c *pluginCommand) Options() any { return c.options }
+
+// Run reports that a plugin is not run in-process; App dispatch execs it instead.
+func (c *pluginCommand) Run(_ GlobalFlags, _ Unknowns) error {
+	return fmt.Errorf("plugin %q runs as an external command: %s", c.Name(""), c.path)
+}
+
+// pluginOptions builds a pointer to a struct with one field per described flag, tagged the way a
+// hand-written options struct would be, so every help renderer reads it through the usual tags.
+func pluginOptions(flags []pluginFlag) any {
+	fields := make([]reflect.StructField, 0, len(flags))
+	for i, flag := range flags {
+		if flag.Name == "" {
+			continue
+		}
+		tags := []string{"arg:" + strconv.Quote(flag.Name)}
+		if flag.Short != "" {
+			tags = append(tags, "short:"+strconv.Quote(flag.Short))
+		}
+		if flag.Env != "" {
+			tags = append(tags, "env:"+strconv.Quote(flag.Env))
+		}
+		if flag.Default != "" {
+			tags = append(tags, "default:"+strconv.Quote(flag.Default))
+		}
+		if flag.Help != "" {
+			tags = append(tags, "help:"+strconv.Quote(flag.Help))
+		}
+		if flag.Required {
+			tags = append(tags, `rules:"required"`)
+		}
+		fields = append(fields, reflect.StructField{
+			Name: "Flag" + strconv.Itoa(i),
+			Type: pluginFlagType(flag.Type),
+			Tag:  reflect.StructTag(strings.Join(tags, " ")),
+		})
+	}
+
+	return reflect.New(reflect.StructOf(fields)).Interface()
+}
+
+// pluginFlagType maps a described flag type to the Go type of its options field; unknown types are strings.
+func pluginFlagType(name string) reflect.Type {
+	switch name {
+	case "bool":
+		return reflect.TypeOf(false)
+	case "int", "int8", "int16", "int32", "int64":
+		return reflect.TypeOf(int64(0))
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return reflect.TypeOf(uint64(0))
+	case "float32", "float64":
+		return reflect.TypeOf(float64(0))
+	case "slice", "[]string":
+		return reflect.TypeOf([]string(nil))
+	case "[]int":
+		return reflect.TypeOf([]int64(nil))
+	default:
+		return reflect.TypeOf("")
+	}
+}
+
+// Plugins enables git-style external commands and returns the app for chaining.
+func (c *app) Plugins() App {
+	c.plugins = true
+
+	return c
+}
+
+// pluginPrefix is the executable name prefix plugins are found by: "<app name>-".
+func (c *app) pluginPrefix() string {
+	return c.config.Name + "-"
+}
+
+// findPlugin reports the plugin osArgs invoke: the first positional, when it names no built-in command
+// and <app>-<name> is an executable on the app's PATH (see pluginDirs). It returns the plugin's path,
+// name, and position in osArgs.
+func (c *app) findPlugin(osArgs []string) (string, string, int, bool) {
+	if !c.plugins || c.config.Name == "" {
+		return "", "", 0, false
+	}
+	name, index := c.firstPositional(osArgs)
+	if name == "" || strings.ContainsAny(name, `/\`) || c.matchCommandByName(name, c.commands) != nil {
+		return "", "", 0, false
+	}
+	path, ok := c.lookPlugin(c.pluginPrefix() + name)
+	if !ok {
+		return "", "", 0, false
+	}
+
+	return path, name, index, true
+}
+
+// runPlugin execs the plugin at path with the args after its name, wired to the app's stdio and
+// an environment describing the app. Global flags before the name apply to the app: --cwd sets the
+// plugin's working directory. A failing plugin's exit code becomes the app's (see ExitCode).
+func (c *app) runPlugin(ctx context.Context, path, name string, osArgs []string, index int) error {
+	globalFlags, _ := c.getGlobalFlags(osArgs[:index])
+	if err := mapStructToOptions(c.globalFlags, globalFlags, argHelpFormat); err != nil {
+		return fmt.Errorf("failed to update global options struct: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, path, osArgs[index+1:]...)
+	streams := c.stdio()
+	cmd.Stdin = streams.In
+	cmd.Stdout = streams.Out
+	cmd.Stderr = streams.Err
+	cmd.Dir = c.globalFlags.Cwd
+	cmd.Env = append(streams.Env.Environ(), c.pluginEnv(name)...)
+	// on cancellation, interrupt the plugin so it can shut down the way a built-in command would,
+	// rather than killing it outright. A Ctrl-C is the exception: the terminal already sent it to the
+	// whole foreground process group, plugin included, and a second one would read as "force exit".
+	cmd.Cancel = func() error {
+		if interruptedBy(ctx) == os.Interrupt {
+			return nil
+		}
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = c.grace
+
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return WithExitCode(fmt.Errorf("plugin %q failed: %w", name, err), exitErr.ExitCode())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run plugin %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// pluginEnv returns the KEY=value pairs describing the app to a plugin invoked as name.
+func (c *app) pluginEnv(name string) []string {
+	env := []string{
+		EnvPluginAppName + "=" + c.config.Name,
+		EnvPluginAppVersion + "=" + c.config.Version,
+		EnvPluginCommand + "=" + name,
+	}
+	if executable, err := os.Executable(); err == nil {
+		env = append(env, EnvPluginAppExecutable+"="+executable)
+	}
+	if c.globalFlags.Cwd != "" {
+		env = append(env, EnvPluginCwd+"="+c.globalFlags.Cwd)
+	}
+
+	return env
+}
+
+// pluginCommands returns the plugins found on the app's PATH, discovering them once per app.
+func (c *app) pluginCommands() []Command[any] {
+	if !c.plugins || c.config.Name == "" {
+		return nil
+	}
+	if c.discovered == nil {
+		c.discovered = c.discoverPlugins()
+	}
+
+	return c.discovered
+}
+
+// discoverPlugins scans pluginDirs for <app>-<cmd> executables, in PATH order (the first of a name wins,
+// as with exec), skipping names a built-in command already answers to. Each is asked to describe
+// itself; one that cannot is still listed, by name.
+func (c *app) discoverPlugins() []Command[any] {
+	prefix := c.pluginPrefix()
+	seen := make(map[string]bool)
+	plugins := make([]Command[any], 0)
+	for _, dir := range c.pluginDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name(), prefix)
+			if !ok || seen[name] || c.matchCommandByName(name, c.commands) != nil {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugin := newPluginCommand(name, path, c.describePlugin(path, name))
+			bindEnvPrefix(plugin, c.config.EnvPrefix)
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	return plugins
+}
+
+// pluginDirs returns the directories on the PATH of the app's environment (see App.IO). Relative
+// entries are skipped, as exec.LookPath refuses them, so a plugin never runs from the working directory.
+func (c *app) pluginDirs() []string {
+	path, _ := c.stdio().Env.LookupEnv("PATH")
+	dirs := make([]string, 0)
+	for _, dir := range filepath.SplitList(path) {
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs
+}
+
+// lookPlugin resolves the executable file against pluginDirs, the first match winning as with exec.LookPath.
+func (c *app) lookPlugin(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		file += ".exe"
+	}
+	for _, dir := range c.pluginDirs() {
+		if path := filepath.Join(dir, file); isExecutable(path) {
+			return path, true
+		}
+	}
+
+	return "", false
+}
+
+// pluginName returns the command name in a plugin file name (prefix + name, plus .exe on Windows).
+func pluginName(file, prefix string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		file = strings.TrimSuffix(strings.ToLower(file), ".exe")
+	}
+	name, ok := strings.CutPrefix(file, prefix)
+	if !ok || name == "" || strings.ContainsAny(name, ". ") {
+		return "", false
+	}
+
+	return name, true
+}
+
+// isExecutable reports whether path is a regular file the user can run.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	return info.Mode().Perm()&0o111 != 0
+}
+
+// describePlugin runs the __describe handshake against the plugin at path. A plugin that fails it,
+// times out, or prints something other than CommandInfo JSON is described by its name alone.
+func (c *app) describePlugin(path, name string) pluginInfo {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginDescribeTimeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, pluginDescribeArg)
+	cmd.Stdout = &out
+	cmd.Env = append(c.stdio().Env.Environ(), c.pluginEnv(name)...)
+
+	info := pluginInfo{}
+	if err := cmd.Run(); err != nil {
+		return info
+	}
+	if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+		return pluginInfo{}
+	}
+
+	return info
+}
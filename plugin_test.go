@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// installPlugin writes an executable shell script named file into dir.
+func installPlugin(t *testing.T, dir, file, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatalf("failed to write plugin: %v", err)
+	}
+}
+
+// pluginPath creates a directory of plugins for the test.
+func pluginPath(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	return t.TempDir()
+}
+
+// newPluginApp returns an app with plugins enabled and dir first on the PATH of its own environment,
+// and the buffer its Out writes to.
+func newPluginApp(dir string) (*app, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	app := newTestApp(Config{Name: "tool", Version: "1.2.3"}, GlobalFlags{})
+	app.IO(IO{Out: out, Err: &bytes.Buffer{}, Env: MapEnv(map[string]string{"PATH": dir + string(os.PathListSeparator) + os.Getenv("PATH")})})
+	app.plugins = true
+	app.Add("help", &recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+	app.Add("build", NewMockCommand(func() error { return nil }))
+	return app, out
+}
+
+func Test_Plugin_Dispatch(t *testing.T) {
+	dir := pluginPath(t)
+	installPlugin(t, dir, "tool-hello", `echo "args:$*"
+echo "app:$CLI_APP_NAME $CLI_APP_VERSION $CLI_PLUGIN_COMMAND"
+pwd
+`)
+	cwd := t.TempDir()
+	app, out := newPluginApp(dir)
+
+	assertNoError(t, app.RunContext(context.Background(), []string{"--cwd", cwd, "hello", "world", "--help", "--name=x"}))
+	assertContains(t, out.String(), "args:world --help --name=x", "the plugin owns the args after its name")
+	assertContains(t, out.String(), "app:tool 1.2.3 hello", "the env describes the parent app")
+	resolved, _ := filepath.EvalSymlinks(cwd)
+	assertContains(t, out.String(), resolved, "--cwd sets the plugin's working directory")
+}
+
+func Test_Plugin_SplitsAtThePositional(t *testing.T) {
+	dir := pluginPath(t)
+	installPlugin(t, dir, "tool-hello", `echo "args:$*"
+pwd
+`)
+	// a --cwd directory named like the plugin is the flag's value, not the plugin's name
+	cwd := filepath.Join(t.TempDir(), "hello")
+	if err := os.Mkdir(cwd, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(filepath.Dir(cwd)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	app, out := newPluginApp(dir)
+
+	assertNoError(t, app.RunContext(context.Background(), []string{"--cwd", "hello", "hello", "world"}))
+	assertContains(t, out.String(), "args:world\n", "the args after the plugin's name, not the flag's value")
+	resolved, _ := filepath.EvalSymlinks(cwd)
+	assertContains(t, out.String(), resolved)
+}
+
+func Test_Plugin_ReadsTheAppPath(t *testing.T) {
+	dir := pluginPath(t)
+	installPlugin(t, dir, "tool-hello", "echo plugin\n")
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "absolute entry", path: dir, want: true},
+		{name: "process PATH is not read", path: "", want: false},
+		{name: "relative entry is skipped", path: ".", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _ := newPluginApp(dir)
+			app.IO(IO{Env: MapEnv(map[string]string{"PATH": tt.path})})
+			_, _, _, ok := app.findPlugin([]string{"hello"})
+			assertEqual(t, tt.want, ok)
+			listed := len(app.Commands()) == 3
+			assertEqual(t, tt.want, listed, "listed in help as it is found")
+		})
+	}
+}
+
+func Test_Plugin_ExitCode(t *testing.T) {
+	dir := pluginPath(t)
+	installPlugin(t, dir, "tool-fail", "exit 3\n")
+
+	app, _ := newPluginApp(dir)
+	err := app.RunContext(context.Background(), []string{"fail"})
+	assertError(t, err)
+	assertEqual(t, 3, ExitCode(err), "the plugin's exit code becomes the app's")
+}
+
+func Test_Plugin_Cancel(t *testing.T) {
+	dir := pluginPath(t)
+	installPlugin(t, dir, "tool-wait", `trap 'echo int >> "$0.log"; exit 0' INT
+touch "$0.ready"
+while :; do sleep 0.05; done
+`)
+	script := filepath.Join(dir, "tool-wait")
+
+	tests := []struct {
+		name  string
+		cause error
+		want  string
+	}{
+		{name: "a programmatic cancel is forwarded", cause: context.Canceled, want: "int\n"},
+		{name: "SIGTERM is forwarded as an interrupt", cause: signalCause{sig: syscall.SIGTERM}, want: "int\n"},
+		{name: "a terminal Ctrl-C is not sent twice", cause: signalCause{sig: os.Interrupt}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Remove(script + ".log")
+			_ = os.Remove(script + ".ready")
+			app, _ := newPluginApp(dir)
+			// with nothing forwarded, the plugin is killed once the grace period runs out
+			app.grace = 300 * time.Millisecond
+
+			ctx, cancel := context.WithCancelCause(context.Background())
+			done := make(chan error, 1)
+			go func() { done <- app.RunContext(ctx, []string{"wait"}) }()
+			waitForFile(t, script+".ready")
+			cancel(tt.cause)
+			<-done
+
+			log, _ := os.ReadFile(script + ".log")
+			assertEqual(t, tt.want, string(log))
+		})
+	}
+}
+
+// waitForFile blocks until path exists, failing the test after a few seconds.
+func waitForFile(t *testing.T, path string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func Test_Plugin_BuiltInWins(t *testing.T) {
+	dir := pluginPath(t)
+	installPlugin(t, dir, "tool-build", "echo plugin\n")
+
+	ran := false
+	app, out := newPluginApp(dir)
+	app.commands[1] = NewMockCommand(func() error { ran = true; return nil })
+	app.commands[1].Name("build")
+
+	assertNoError(t, app.RunContext(context.Background(), []string{"build"}))
+	assertEqual(t, true, ran, "the built-in command runs")
+	assertEqual(t, false, strings.Contains(out.String(), "plugin"), "the plugin is shadowed")
+	for _, cmd := range app.Commands() {
+		if _, ok := cmd.(*pluginCommand); ok {
+			t.Fatalf("shadowed plugin %q listed", cmd.Name(""))
+		}
+	}
+}
+
+func Test_Plugin_Disabled(t *testing.T) {
+	dir := pluginPath(t)
+	installPlugin(t, dir, "tool-hello", "echo plugin\n")
+
+	app, out := newPluginApp(dir)
+	app.plugins = false
+	assertErrorIs(t, app.RunContext(context.Background(), []string{"hello"}), ErrCommandNotFound)
+	assertEqual(t, false, strings.Contains(out.String(), "plugin"), "plugins only run once enabled")
+	assertLen(t, app.Commands(), 2)
+}
+
+func Test_Plugin_Describe(t *testing.T) {
+	dir := pluginPath(t)
+	installPlugin(t, dir, "tool-deploy", `[ "$1" = "__describe" ] || exit 1
+cat <<'EOF'
+{"name":"deploy","help":"Deploy the app","description":"Ships it.",
+ "flags":[{"name":"target","short":"t","help":"Where to","type":"string","required":true},
+          {"name":"dry-run","type":"bool"}],
+ "subcommands":[{"name":"status","help":"Show the rollout"}]}
+EOF
+`)
+	installPlugin(t, dir, "tool-raw", "exit 0\n")
+	installPlugin(t, dir, "tool-notes.txt", "")
+	if err := os.Chmod(filepath.Join(dir, "tool-notes.txt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	app, _ := newPluginApp(dir)
+	commands := app.Commands()
+	assertLen(t, commands, 4, "built-ins, then the executable plugins")
+
+	deploy := app.matchCommandByName("deploy", commands)
+	assertNotNil(t, deploy)
+	assertEqual(t, "Deploy the app", deploy.Help())
+	assertEqual(t, "Ships it.", deploy.Description())
+	assertLen(t, deploy.Commands(), 1)
+	assertEqual(t, "status", deploy.Commands()[0].Name(""))
+
+	fields := fieldTags(t, deploy.Options(), "arg")
+	assertEqual(t, "target,dry-run", strings.Join(fields, ","), "described flags become options")
+
+	raw := app.matchCommandByName("raw", commands)
+	assertNotNil(t, raw)
+	assertContains(t, raw.Help(), "tool-raw", "an undescribed plugin is listed by its path")
+
+	out := completeOutput(app, "deploy", "--")
+	assertContains(t, out, "--target\tWhere to")
+	assertContains(t, out, "--dry-run")
+
+	assertContains(t, completeOutput(app, ""), "deploy\tDeploy the app")
+}
+
+// fieldTags lists the tag values of the struct options points to.
+func fieldTags(t *testing.T, options any, tag string) []string {
+	t.Helper()
+	typ := reflect.TypeOf(options).Elem()
+	values := make([]string, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		values = append(values, typ.Field(i).Tag.Get(tag))
+	}
+	return values
+}
@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/toaweme/cli/internal/term"
+	"github.com/toaweme/structs"
+)
+
+// Question is what a Prompter asks for one required input left empty after the merge.
+type Question struct {
+	// Name is how the field is shown to a user: "--region", or "argument 1" for a positional.
+	Name string
+	// Help is the field's help text, "" when it has none.
+	Help string
+	// Choices are the values allowed by the field's oneof rule, offered as a selection list.
+	Choices []string
+	// Secret is set for a field tagged secret:"true"; its answer must not be echoed.
+	Secret bool
+	// Retry says why the previous answer was rejected, "" on the first ask.
+	Retry string
+}
+
+// Prompter asks the user for required inputs still empty after the merge, instead of failing validation.
+type Prompter interface {
+	// Interactive reports whether there is a user to ask; a non-interactive Prompter is never asked.
+	Interactive() bool
+	// Ask poses q and returns the raw answer: a choice's value or its 1-based number for a selection.
+	Ask(q Question) (string, error)
+}
+
+// terminalPrompter asks on a terminal: questions go to out, answers are read a line at a time from in.
+type terminalPrompter struct {
+	in     io.Reader
+	out    io.Writer
+	tty    bool
+	reader *bufio.Reader
+}
+
+// NewTerminalPrompter returns the Prompter the App uses by default, reading answers from in and writing
+// questions to out. It is interactive only when in is a terminal, so piped and CI runs never block on it.
+func NewTerminalPrompter(in io.Reader, out io.Writer) Prompter {
+	return &terminalPrompter{in: in, out: out, tty: term.IsTerminal(in)}
+}
+
+func (p *terminalPrompter) Interactive() bool {
+	return p.tty
+}
+
+func (p *terminalPrompter) Ask(q Question) (string, error) {
+	return p.ask(q, nil)
+}
+
+// ask is Ask, restoring echo on the shutdown signals of the run signals belongs to (nil outside of Run).
+func (p *terminalPrompter) ask(q Question, signals *runSignals) (string, error) {
+	if q.Retry != "" {
+		fmt.Fprintf(p.out, "%s\n", q.Retry)
+	}
+	label := q.Name
+	if q.Help != "" {
+		label = q.Help + " (" + q.Name + ")"
+	}
+	if len(q.Choices) > 0 {
+		fmt.Fprintf(p.out, "%s:\n", label)
+		for i, choice := range q.Choices {
+			fmt.Fprintf(p.out, "  %d) %s\n", i+1, choice)
+		}
+		fmt.Fprintf(p.out, "Choose 1-%d: ", len(q.Choices))
+	} else {
+		fmt.Fprintf(p.out, "%s: ", label)
+	}
+
+	if q.Secret {
+		// echo comes back on when the answer is read, and also on Ctrl-C: the read stays blocked
+		// after the first, and a second force-exits the process without returning here.
+		restore := p.hideInput()
+		release := signals.onSignal(restore)
+		defer func() {
+			release()
+			restore()
+			fmt.Fprintln(p.out)
+		}()
+	}
+
+	return p.readLine()
+}
+
+// readLine reads one answer, without its line ending. A final line without one is still an answer.
+func (p *terminalPrompter) readLine() (string, error) {
+	if p.reader == nil {
+		p.reader = bufio.NewReader(p.in)
+	}
+	line, err := p.reader.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// hideInput turns off terminal echo for a secret answer and returns the func that turns it back on.
+// It is best effort: off a Unix terminal the answer is echoed.
+func (p *terminalPrompter) hideInput() func() {
+	f, ok := p.in.(*os.File)
+	if !ok || !p.tty {
+		return func() {}
+	}
+	state, err := term.DisableEcho(f)
+	if err != nil {
+		return func() {}
+	}
+
+	return func() { _ = term.Restore(f, state) }
+}
+
+// Prompter sets how the app asks for required inputs left empty, and returns the app for chaining.
+func (c *app) Prompter(p Prompter) App {
+	c.prompter = p
+
+	return c
+}
+
+// runPrompter is a terminalPrompter asking under a Run, so a secret answer's echo comes back on its signals.
+type runPrompter struct {
+	*terminalPrompter
+	signals *runSignals
+}
+
+func (p runPrompter) Ask(q Question) (string, error) {
+	return p.ask(q, p.signals)
+}
+
+// interactive returns the Prompter to ask for missing inputs under ctx, or nil when the user can't be
+// asked: --no-input was given or there is no terminal.
+func (c *app) interactive(ctx context.Context) Prompter {
+	if c.globalFlags.NoInput {
+		return nil
+	}
+	p := c.prompter
+	if p == nil {
+		streams := c.stdio()
+		// read answers through the app's reader of In, which a shell reading lines also shares
+		p = &terminalPrompter{in: streams.In, out: streams.Err, tty: term.IsTerminal(streams.In), reader: streams.Reader()}
+	}
+	if !p.Interactive() {
+		return nil
+	}
+	if tp, ok := p.(*terminalPrompter); ok {
+		return runPrompter{terminalPrompter: tp, signals: signalsOf(ctx)}
+	}
+
+	return p
+}
+
+// promptMissing asks for every required field of options missing after the merge, sets the answers,
+// and adds them to flags so validation counts them as given. Rejected answers are asked again.
+func (c *app) promptMissing(ctx context.Context, options any, flags map[string]any) error {
+	p := c.interactive(ctx)
+	if p == nil {
+		return nil
+	}
+	fields, err := structs.GetStructFields(options, nil, structs.DefaultEncodingTags)
+	if err != nil {
+		return fmt.Errorf("failed to get struct fields: %w", err)
+	}
+
+	manager := structs.New(options, structs.WithTags(defaultTags...))
+	inputs := c.validationInputs(options, flags)
+	var failed error
+	walkFields(fields, "", func(field structs.Field, _ string) {
+		if failed != nil || !needsPrompt(field, inputs) {
+			return
+		}
+		arg := fieldTag(field, tagArg)
+		q := newQuestion(field)
+		for {
+			answer, err := p.Ask(q)
+			if err != nil {
+				failed = fmt.Errorf("failed to prompt for %s: %w", q.Name, err)
+				return
+			}
+			value, retry := answerValue(q, answer)
+			if retry == "" {
+				if err := setAnswer(manager, field, arg, value); err != nil {
+					shown := value
+					if q.Secret {
+						shown = redacted
+					}
+					retry = fmt.Sprintf("invalid value %q for %s", shown, q.Name)
+				}
+			}
+			if retry == "" {
+				flags[arg] = value
+				return
+			}
+			q.Retry = retry
+		}
+	})
+
+	return failed
+}
+
+// setAnswer sets field to an answer, parsed from text for a type that parses itself (see Value) or a map.
+func setAnswer(manager *structs.Manager, field structs.Field, arg, value string) error {
+	if isDecodedType(field.Value.Type()) {
+		return setText(field, value)
+	}
+
+	return manager.Set(map[string]any{arg: value})
+}
+
+// needsPrompt reports whether field is required, settable by name, and missing: no flag or env var in
+// inputs gives it, and no other layer (a default, a config file) filled it in. A flag or env var giving
+// the zero value, such as --port 0 or --name "", is an answer already.
+func needsPrompt(field structs.Field, inputs map[string]any) bool {
+	if fieldTag(field, tagArg) == "" || !field.Value.IsValid() || !field.Value.IsZero() {
+		return false
+	}
+	if _, given := fieldInput(field, inputs); given {
+		return false
+	}
+
+	return slices.ContainsFunc(field.Rules, func(rule structs.Rule) bool { return rule.Name == "required" })
+}
+
+// newQuestion describes field as a Question.
+func newQuestion(field structs.Field) Question {
+	q := Question{Help: field.Tags["help"]}
+	if arg := fieldTag(field, tagArg); isPositional(arg) {
+		n, _ := strconv.Atoi(arg)
+		q.Name = "argument " + strconv.Itoa(n+1)
+	} else {
+		q.Name = "--" + arg
+	}
+	if secret, ok := field.Tags["secret"]; ok && truthy(secret) {
+		q.Secret = true
+	}
+	for _, rule := range field.Rules {
+		if rule.Name == "oneof" {
+			q.Choices = rule.Args
+		}
+	}
+
+	return q
+}
+
+// answerValue maps answer to the value to set, accepting a choice by value or by number, or returns why
+// it is rejected. Surrounding space is trimmed, except from a secret.
+func answerValue(q Question, answer string) (string, string) {
+	if !q.Secret {
+		answer = strings.TrimSpace(answer)
+	}
+	if strings.TrimSpace(answer) == "" {
+		return "", q.Name + " is required"
+	}
+	if len(q.Choices) == 0 || slices.Contains(q.Choices, answer) {
+		return answer, ""
+	}
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(q.Choices) {
+		return q.Choices[n-1], ""
+	}
+
+	return "", fmt.Sprintf("%s must be one of %s", q.Name, strings.Join(q.Choices, ", "))
+}
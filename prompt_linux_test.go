@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/toaweme/cli/internal/termtest"
+)
+
+func Test_TerminalPrompter_SecretEchoRestoredOnSignal(t *testing.T) {
+	ptm, pts := termtest.OpenPTY(t)
+	p := &terminalPrompter{in: pts, out: &bytes.Buffer{}, tty: true}
+	signals := &runSignals{}
+
+	type result struct {
+		answer string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		answer, err := runPrompter{terminalPrompter: p, signals: signals}.Ask(Question{Name: "--token", Secret: true})
+		done <- result{answer, err}
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for termtest.Echo(t, pts) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected echo off while the secret is read")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Ctrl-C: the read is still blocked, but echo is back on for a force exit to leave behind
+	signals.runHooks()
+	assertEqual(t, true, termtest.Echo(t, pts), "echo restored on the signal")
+
+	if _, err := ptm.WriteString("s3cret\n"); err != nil {
+		t.Fatal(err)
+	}
+	got := <-done
+	assertNoError(t, got.err)
+	assertEqual(t, "s3cret", got.answer)
+	assertEqual(t, true, termtest.Echo(t, pts))
+	assertLen(t, signals.hooks, 0, "the hook is released once the answer is read")
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type promptConfig struct {
+	Target string `arg:"0" help:"Where to deploy" rules:"required"`
+	Region string `arg:"region" help:"Region" rules:"required|oneof:eu,us"`
+	Token  string `arg:"token" help:"API token" secret:"true" rules:"required"`
+	Port   int    `arg:"port" help:"Port" default:"80" rules:"required"`
+	Note   string `arg:"note" help:"Note"`
+}
+
+type promptCommand struct {
+	BaseCommand[promptConfig]
+	ran bool
+}
+
+func (c *promptCommand) Help() string { return "deploy" }
+func (c *promptCommand) Run(_ GlobalFlags, _ Unknowns) error {
+	c.ran = true
+	return nil
+}
+
+// scriptedPrompter answers questions from a script, recording what it was asked.
+type scriptedPrompter struct {
+	answers []string
+	asked   []Question
+}
+
+func (p *scriptedPrompter) Interactive() bool { return true }
+func (p *scriptedPrompter) Ask(q Question) (string, error) {
+	p.asked = append(p.asked, q)
+	answer := p.answers[0]
+	p.answers = p.answers[1:]
+	return answer, nil
+}
+
+func newPromptApp(p Prompter) (*app, *promptCommand) {
+	app := newTestApp(Config{Name: "app"}, GlobalFlags{})
+	app.Add("help", &recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+	cmd := &promptCommand{BaseCommand: NewBaseCommand[promptConfig]()}
+	app.Add("deploy", cmd)
+	if p != nil {
+		app.Prompter(p)
+	}
+	return app, cmd
+}
+
+func Test_Prompt_MissingRequired(t *testing.T) {
+	p := &scriptedPrompter{answers: []string{"prod", "", "mars", "2", "s3cret"}}
+	app, cmd := newPromptApp(p)
+
+	assertNoError(t, app.Run([]string{"deploy"}))
+	assertEqual(t, true, cmd.ran)
+	opts := cmd.Options().(*promptConfig)
+	assertEqual(t, "prod", opts.Target)
+	assertEqual(t, "us", opts.Region, "a choice can be picked by number")
+	assertEqual(t, "s3cret", opts.Token)
+	assertEqual(t, 80, opts.Port, "a defaulted field is not asked for")
+
+	assertLen(t, p.asked, 5)
+	assertEqual(t, Question{Name: "argument 1", Help: "Where to deploy"}, p.asked[0])
+	assertEqual(t, "--region is required", p.asked[2].Retry)
+	assertEqual(t, "--region must be one of eu, us", p.asked[3].Retry)
+	assertEqual(t, "eu,us", strings.Join(p.asked[3].Choices, ","))
+	assertEqual(t, true, p.asked[4].Secret)
+}
+
+func Test_Prompt_OnlyMissing(t *testing.T) {
+	p := &scriptedPrompter{answers: []string{"tok"}}
+	app, cmd := newPromptApp(p)
+
+	assertNoError(t, app.Run([]string{"deploy", "prod", "--region", "eu"}))
+	assertLen(t, p.asked, 1, "given inputs are not asked for")
+	assertEqual(t, "tok", cmd.Options().(*promptConfig).Token)
+}
+
+func Test_Prompt_ExplicitZeroIsGiven(t *testing.T) {
+	p := &scriptedPrompter{}
+	app, cmd := newPromptApp(p)
+
+	assertNoError(t, app.Run([]string{"deploy", "prod", "--region", "eu", "--token", "t", "--port", "0"}))
+	assertLen(t, p.asked, 0, "--port 0 is not asked for")
+	assertEqual(t, 0, cmd.Options().(*promptConfig).Port)
+
+	app, cmd = newPromptApp(p)
+	err := app.Run([]string{"deploy", "prod", "--region", "eu", "--token", ""})
+	assertLen(t, p.asked, 0, `--token "" is not asked for`)
+	assertErrorIs(t, err, ErrValidationFailed, "an explicit empty value fails validation instead")
+	assertEqual(t, false, cmd.ran)
+}
+
+type portConfig struct {
+	Port int `arg:"port" rules:"required"`
+}
+
+type portCommand struct {
+	BaseCommand[portConfig]
+}
+
+func (c *portCommand) Help() string                        { return "serve" }
+func (c *portCommand) Run(_ GlobalFlags, _ Unknowns) error { return nil }
+
+func Test_Prompt_InvalidType(t *testing.T) {
+	p := &scriptedPrompter{answers: []string{"eighty", "8080"}}
+	app := newTestApp(Config{Name: "app"}, GlobalFlags{})
+	cmd := &portCommand{BaseCommand: NewBaseCommand[portConfig]()}
+	app.Add("serve", cmd)
+	app.Prompter(p)
+
+	assertNoError(t, app.Run([]string{"serve"}))
+	assertEqual(t, `invalid value "eighty" for --port`, p.asked[1].Retry)
+	assertEqual(t, 8080, cmd.Options().(*portConfig).Port)
+}
+
+func Test_Prompt_NoInput(t *testing.T) {
+	p := &scriptedPrompter{}
+	app, cmd := newPromptApp(p)
+
+	err := app.Run([]string{"deploy", "--no-input"})
+	assertErrorIs(t, err, ErrValidationFailed)
+	assertLen(t, p.asked, 0)
+	assertEqual(t, false, cmd.ran)
+}
+
+func Test_Prompt_NotATerminal(t *testing.T) {
+	app, _ := newPromptApp(nil)
+	app.IO(IO{In: strings.NewReader("prod\n"), Err: &bytes.Buffer{}})
+
+	err := app.Run([]string{"deploy"})
+	assertErrorIs(t, err, ErrValidationFailed, "without a terminal the run fails as before")
+}
+
+func Test_TerminalPrompter_Ask(t *testing.T) {
+	var out bytes.Buffer
+	p := &terminalPrompter{in: strings.NewReader("2\r\nlast"), out: &out, tty: true}
+
+	answer, err := p.Ask(Question{Name: "--region", Help: "Region", Choices: []string{"eu", "us"}, Retry: "--region is required"})
+	assertNoError(t, err)
+	assertEqual(t, "2", answer)
+	assertEqual(t, "--region is required\nRegion (--region):\n  1) eu\n  2) us\nChoose 1-2: ", out.String())
+
+	out.Reset()
+	answer, err = p.Ask(Question{Name: "argument 1"})
+	assertNoError(t, err)
+	assertEqual(t, "last", answer, "a final line without a newline is an answer")
+	assertEqual(t, "argument 1: ", out.String())
+
+	_, err = p.Ask(Question{Name: "--token"})
+	assertError(t, err, "EOF ends the prompt")
+}
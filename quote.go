@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrUnterminatedQuote is returned by SplitArgs for text ending inside quotes or after a lone backslash.
+var ErrUnterminatedQuote = errors.New("unterminated quote or escape")
+
+// SplitArgs breaks text into arguments the way a POSIX shell does, without expansion: whitespace
+// (newlines and carriage returns included) separates words, single quotes keep everything literally,
+// double quotes keep everything but a backslash before `"`, `\`, `$`, or "`", a backslash outside quotes
+// escapes the next character, a backslash before a line ending joins the lines, and a # starting a word
+// comments out the rest of its line.
+// `deploy --note "it's done"` is ["deploy", "--note", "it's done"].
+func SplitArgs(text string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+		comment bool
+	)
+
+	for _, r := range text {
+		switch {
+		case comment:
+			comment = r != '\n'
+		case escaped && r == '\r':
+			// the CR of a CRLF line continuation; the LF ends it
+		case escaped && r == '\n':
+			escaped = false
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			inWord = true
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '#' && !inWord:
+			comment = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, ErrUnterminatedQuote
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// QuoteArg returns arg as SplitArgs reads it back: unchanged when it needs no quoting,
+// single-quoted otherwise.
+func QuoteArg(arg string) string {
+	if arg != "" && !strings.HasPrefix(arg, "#") && !strings.ContainsAny(arg, " \t\r\n'\"\\$`") {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
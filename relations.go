@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/toaweme/structs"
+)
+
+// The relation rules, each declared by the tag of the same name on a config field and checked against
+// the merged inputs, after the single-field rules (see checkRelations). A field counts as set when its
+// merged value is not the zero value, whichever layer it came from.
+//
+//	File  string `arg:"file" exclusive:"source"`       // at most one of the "source" group is set
+//	Stdin bool   `arg:"stdin" exclusive:"source"`
+//	ID    string `arg:"id" onerequired:"target"`       // exactly one of the "target" group is set
+//	Name  string `arg:"name" onerequired:"target"`
+//	Key   string `arg:"tls-key" requires:"tls-cert"`   // set only together with --tls-cert (comma-separated)
+//	Token string `arg:"token" requiredif:"auth=token"` // required when --auth is "token" (bare "auth": when set at all)
+const (
+	RuleExclusive   = "exclusive"
+	RuleOneRequired = "onerequired"
+	RuleRequires    = "requires"
+	RuleRequiredIf  = "requiredif"
+)
+
+// Relation is one cross-field rule declared on a config struct.
+type Relation struct {
+	// Rule is RuleExclusive, RuleOneRequired, RuleRequires, or RuleRequiredIf.
+	Rule string
+	// Flag is the flag declaring a requires or requiredif rule, "" for a group.
+	Flag string
+	// Flags are the group's members (exclusive, onerequired), the flags Flag requires (requires),
+	// or the one flag whose value makes Flag required (requiredif). Flags are named without dashes.
+	Flags []string
+	// Value is the value of Flags[0] making Flag required (requiredif), "" for any value it is set to.
+	Value string
+}
+
+// FieldRelations returns the relations declared by the tags of fields and their nested fields, in the
+// order they are declared; a group is placed where its first member is.
+func FieldRelations(fields []structs.Field) []Relation {
+	var relations []Relation
+	groups := make(map[string]int)
+	walkFields(fields, "", func(field structs.Field, _ string) {
+		arg := fieldTag(field, tagArg)
+		if arg == "" || isPositional(arg) {
+			return
+		}
+		for _, rule := range []string{RuleExclusive, RuleOneRequired} {
+			group := field.Tags[rule]
+			if group == "" {
+				continue
+			}
+			key := rule + ":" + group
+			if i, ok := groups[key]; ok {
+				relations[i].Flags = append(relations[i].Flags, arg)
+				continue
+			}
+			groups[key] = len(relations)
+			relations = append(relations, Relation{Rule: rule, Flags: []string{arg}})
+		}
+		if requires := field.Tags[RuleRequires]; requires != "" {
+			relations = append(relations, Relation{Rule: RuleRequires, Flag: arg, Flags: splitList(requires)})
+		}
+		if cond := field.Tags[RuleRequiredIf]; cond != "" {
+			flag, value, _ := strings.Cut(cond, "=")
+			relations = append(relations, Relation{Rule: RuleRequiredIf, Flag: arg, Flags: []string{flag}, Value: value})
+		}
+	})
+
+	return relations
+}
+
+// splitList splits a comma-separated tag value, trimming each item and dropping empty ones.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// relationField is a field a relation names, with its dotted Go path for error reporting.
+type relationField struct {
+	field structs.Field
+	path  string
+}
+
+// isSet reports whether the field holds a non-zero value after the merge.
+func (f relationField) isSet() bool {
+	return f.field.Value.IsValid() && !f.field.Value.IsZero()
+}
+
+// checkRelations checks the relation rules declared on options against its merged values, returning a
+// *ValidationError listing every broken one, nil when all hold. A rule naming a flag options doesn't
+// have is a mistake in the struct, reported as a plain error.
+func checkRelations(options any, envPrefix string) error {
+	fields, err := structs.GetStructFields(options, nil, structs.DefaultEncodingTags)
+	if err != nil {
+		return fmt.Errorf("failed to get struct fields: %w", err)
+	}
+	applyEnvNames(fields, envPrefix)
+
+	return checkRelationRules(fields, FieldRelations(fields))
+}
+
+// checkRelationRules checks relations against the merged values of fields, as checkRelations does.
+func checkRelationRules(fields []structs.Field, relations []Relation) error {
+	byArg := make(map[string]relationField)
+	walkFields(fields, "", func(field structs.Field, path string) {
+		if arg := fieldTag(field, tagArg); arg != "" {
+			byArg[arg] = relationField{field: field, path: path}
+		}
+	})
+	lookup := func(relation Relation, flag string) (relationField, error) {
+		f, ok := byArg[flag]
+		if !ok && relation.Flag == "" {
+			return f, fmt.Errorf("failed to check %s rule: no flag --%s", relation.Rule, flag)
+		}
+		if !ok {
+			return f, fmt.Errorf("failed to check %s rule on --%s: no flag --%s", relation.Rule, relation.Flag, flag)
+		}
+		return f, nil
+	}
+
+	verr := &ValidationError{}
+	for _, relation := range relations {
+		switch relation.Rule {
+		case RuleExclusive, RuleOneRequired:
+			var members, set []relationField
+			for _, flag := range relation.Flags {
+				f, err := lookup(relation, flag)
+				if err != nil {
+					return err
+				}
+				members = append(members, f)
+				if f.isSet() {
+					set = append(set, f)
+				}
+			}
+			if len(set) == 0 && relation.Rule == RuleOneRequired {
+				verr.Fields = append(verr.Fields, relationError(members[0], relation.Rule, oneRequiredMessage(relation.Flags[1:])))
+			}
+			for _, f := range set[min(1, len(set)):] {
+				verr.Fields = append(verr.Fields, relationError(f, relation.Rule, "cannot be used with --"+fieldTag(set[0].field, tagArg)))
+			}
+		case RuleRequires:
+			f := byArg[relation.Flag]
+			if !f.isSet() {
+				continue
+			}
+			for _, flag := range relation.Flags {
+				required, err := lookup(relation, flag)
+				if err != nil {
+					return err
+				}
+				if !required.isSet() {
+					verr.Fields = append(verr.Fields, relationError(f, relation.Rule, "requires --"+flag))
+				}
+			}
+		case RuleRequiredIf:
+			f := byArg[relation.Flag]
+			dep, err := lookup(relation, relation.Flags[0])
+			if err != nil {
+				return err
+			}
+			if f.isSet() || !dep.isSet() {
+				continue
+			}
+			if relation.Value == "" {
+				verr.Fields = append(verr.Fields, relationError(f, relation.Rule, "is required when --"+relation.Flags[0]+" is set"))
+			} else if fmt.Sprint(dep.field.Value.Interface()) == relation.Value {
+				verr.Fields = append(verr.Fields, relationError(f, relation.Rule, "is required when --"+relation.Flags[0]+" is "+relation.Value))
+			}
+		}
+	}
+	if len(verr.Fields) == 0 {
+		return nil
+	}
+
+	return verr
+}
+
+// oneRequiredMessage completes "--id ..." for a one-required group none of whose flags is set.
+func oneRequiredMessage(others []string) string {
+	names := make([]string, len(others))
+	for i, other := range others {
+		names[i] = "--" + other
+	}
+	switch len(names) {
+	case 0:
+		return "is required"
+	case 1:
+		return "or " + names[0] + " is required"
+	}
+
+	return "or one of " + strings.Join(names, ", ") + " is required"
+}
+
+// relationError is the FieldError for a broken relation rule on f.
+func relationError(f relationField, rule, message string) FieldError {
+	fe := fieldError(f.field, f.path, rule)
+	fe.Message = message
+
+	return fe
+}
+
+// joinValidation merges the relation failures in rerr into err when err is itself a *ValidationError
+// (so one run reports every failure), and otherwise returns whichever is set, err first.
+func joinValidation(err, rerr error) error {
+	var invalid, broken *ValidationError
+	if errors.As(err, &invalid) && errors.As(rerr, &broken) {
+		invalid.Fields = append(invalid.Fields, broken.Fields...)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	return rerr
+}
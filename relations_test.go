@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/toaweme/structs"
+)
+
+type relationsConfig struct {
+	File    string `arg:"file" exclusive:"source" help:"Read from a file"`
+	Stdin   bool   `arg:"stdin" exclusive:"source" help:"Read from stdin"`
+	ID      string `arg:"id" onerequired:"target" help:"Target by id"`
+	Name    string `arg:"name" short:"n" onerequired:"target" help:"Target by name"`
+	TLSKey  string `arg:"tls-key" env:"TLS_KEY" requires:"tls-cert" help:"TLS key"`
+	TLSCert string `arg:"tls-cert" help:"TLS certificate"`
+	Auth    string `arg:"auth" help:"Auth method"`
+	Token   string `arg:"token" requiredif:"auth=token" help:"Auth token"`
+}
+
+type relationsCommand struct {
+	BaseCommand[relationsConfig]
+}
+
+func (c *relationsCommand) Help() string                        { return "fetch" }
+func (c *relationsCommand) Run(_ GlobalFlags, _ Unknowns) error { return nil }
+
+func Test_App_Relations(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "all hold", args: []string{"--id", "1", "--file", "x", "--tls-key", "k", "--tls-cert", "c", "--auth", "token", "--token", "t"}},
+		{name: "other auth needs no token", args: []string{"--name", "x", "--auth", "basic"}},
+		{name: "exclusive", args: []string{"--id", "1", "--file", "x", "--stdin"}, want: []string{"--stdin cannot be used with --file"}},
+		{name: "none of one-required", args: []string{"--file", "x"}, want: []string{"--id or --name is required"}},
+		{name: "two of one-required", args: []string{"--id", "1", "-n", "x"}, want: []string{"--name (-n) cannot be used with --id"}},
+		{name: "requires", args: []string{"--id", "1", "--tls-key", "k"}, want: []string{"--tls-key ($TLS_KEY) requires --tls-cert"}},
+		{name: "required if", args: []string{"--id", "1", "--auth", "token"}, want: []string{"--token is required when --auth is token"}},
+		{name: "every failure", args: []string{"--stdin", "--file", "x", "--auth=token"}, want: []string{
+			"--stdin cannot be used with --file",
+			"--id or --name is required",
+			"--token is required when --auth is token",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(Config{}, GlobalFlags{})
+			app.Add("help", &recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+			app.Add("fetch", &relationsCommand{BaseCommand: NewBaseCommand[relationsConfig]()})
+
+			err := app.Run(append([]string{"fetch"}, tt.args...))
+			if len(tt.want) == 0 {
+				assertNoError(t, err)
+				return
+			}
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expected a *ValidationError, got %T: %v", err, err)
+			}
+			got := make([]string, 0, len(invalid.Fields))
+			for _, field := range invalid.Fields {
+				got = append(got, field.Error())
+			}
+			assertEqual(t, tt.want, got)
+		})
+	}
+}
+
+func Test_App_Relations_JoinsFieldRules(t *testing.T) {
+	type config struct {
+		Mode string `arg:"mode" rules:"required"`
+		Key  string `arg:"key" requires:"cert"`
+		Cert string `arg:"cert"`
+	}
+	fields, err := structs.GetStructFields(&config{Key: "k"}, nil, structs.DefaultEncodingTags)
+	assertNoError(t, err)
+	assertEqual(t, []Relation{{Rule: RuleRequires, Flag: "key", Flags: []string{"cert"}}}, FieldRelations(fields))
+
+	err = joinValidation(&ValidationError{Fields: []FieldError{{Field: "Mode"}}}, checkRelations(&config{Key: "k"}, ""))
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected a *ValidationError, got %T: %v", err, err)
+	}
+	assertLen(t, invalid.Fields, 2, "the relation failure joins the field failure")
+}
+
+func Test_checkRelations_UnknownFlag(t *testing.T) {
+	type config struct {
+		Key string `arg:"key" requires:"cert"`
+	}
+	err := checkRelations(&config{Key: "k"}, "")
+	assertError(t, err)
+	assertContains(t, err.Error(), "failed to check requires rule on --key: no flag --cert")
+}
+
+func Test_checkRelations_UnknownGroupMember(t *testing.T) {
+	type config struct {
+		File  string `arg:"file"`
+		Stdin bool   `arg:"stdin"`
+	}
+	fields, err := structs.GetStructFields(&config{File: "f"}, nil, structs.DefaultEncodingTags)
+	assertNoError(t, err)
+
+	for _, rule := range []string{RuleExclusive, RuleOneRequired} {
+		err := checkRelationRules(fields, []Relation{{Rule: rule, Flags: []string{"file", "stdn"}}})
+		assertError(t, err)
+		assertContains(t, err.Error(), "failed to check "+rule+" rule: no flag --stdn")
+	}
+}
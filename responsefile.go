@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// responseFilePrefix marks an argument naming a response file: "@args.txt". Doubled ("@@x"), it escapes
+// an argument that really starts with "@".
+const responseFilePrefix = "@"
+
+// maxResponseFileDepth bounds how deeply response files may include others, which also stops a cycle.
+const maxResponseFileDepth = 8
+
+// ResponseFiles turns expansion of "@file" arguments on or off, and returns the app for chaining.
+func (c *app) ResponseFiles(enabled bool) App {
+	c.responseFiles = enabled
+
+	return c
+}
+
+// expandResponseFiles replaces every "@path" in args with the arguments read from path, split like
+// a shell splits them (see SplitArgs). Response files may name others, resolved relative to the
+// including file, up to maxResponseFileDepth; "@@x" stands for a literal "@x", and nothing after a
+// "--" terminator is touched. dir is what relative top-level paths resolve against ("" for the
+// working directory). It also reports whether a terminator was met, inside a response file or not.
+func expandResponseFiles(args []string, dir string, depth int) ([]string, bool, error) {
+	expanded := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == argsTerminator {
+			return append(expanded, args[i:]...), true, nil
+		}
+		if strings.HasPrefix(arg, responseFilePrefix+responseFilePrefix) {
+			expanded = append(expanded, arg[len(responseFilePrefix):])
+			continue
+		}
+		path, ok := strings.CutPrefix(arg, responseFilePrefix)
+		if !ok || path == "" {
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		if depth >= maxResponseFileDepth {
+			return nil, false, fmt.Errorf("failed to expand response file %q: nested more than %d deep", path, maxResponseFileDepth)
+		}
+		if dir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read response file: %w", err)
+		}
+		words, err := SplitArgs(string(data))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse response file %q: %w", path, err)
+		}
+		words, terminated, err := expandResponseFiles(words, filepath.Dir(path), depth+1)
+		if err != nil {
+			return nil, false, err
+		}
+		expanded = append(expanded, words...)
+		if terminated {
+			return append(expanded, args[i+1:]...), true, nil
+		}
+	}
+
+	return expanded, false, nil
+}
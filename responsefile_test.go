@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeResponseFile writes content to name in dir and returns its path.
+func writeResponseFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write response file: %v", err)
+	}
+	return path
+}
+
+func Test_ResponseFiles_Expand(t *testing.T) {
+	dir := t.TempDir()
+	writeResponseFile(t, dir, "nested/more.args", "--tag b # trailing comment\n")
+	main := writeResponseFile(t, dir, "nested/main.args", `# deploy flags
+--env prod
+--note "it's \"done\""
+@more.args
+@@literal
+`)
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "expands in place", args: []string{"deploy", "@" + main, "--tag", "c"}, want: []string{"deploy", "--env", "prod", "--note", `it's "done"`, "--tag", "b", "@literal", "--tag", "c"}},
+		{name: "escaped at", args: []string{"@@handle"}, want: []string{"@handle"}},
+		{name: "bare at", args: []string{"@"}, want: []string{"@"}},
+		{name: "not after the terminator", args: []string{"run", "--", "@" + main}, want: []string{"run", "--", "@" + main}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := expandResponseFiles(tt.args, "", 0)
+			assertNoError(t, err)
+			assertEqual(t, strings.Join(tt.want, "|"), strings.Join(got, "|"))
+		})
+	}
+}
+
+func Test_ResponseFiles_LineEndings(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "CRLF", content: "--env prod\r\n--note \"a b\"\r\n# comment\r\n--tag x\r\n", want: []string{"--env", "prod", "--note", "a b", "--tag", "x"}},
+		{name: "line continuation", content: "--tags a,\\\nb \\\n--env prod\n", want: []string{"--tags", "a,b", "--env", "prod"}},
+		{name: "CRLF line continuation", content: "--tags a,\\\r\nb \\\r\n--env prod\r\n", want: []string{"--tags", "a,b", "--env", "prod"}},
+		{name: "continuation in double quotes", content: "--note \"a \\\nb\"\n", want: []string{"--note", "a b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeResponseFile(t, dir, "args", tt.content)
+			got, _, err := expandResponseFiles([]string{"@" + path}, "", 0)
+			assertNoError(t, err)
+			assertEqual(t, strings.Join(tt.want, "|"), strings.Join(got, "|"))
+		})
+	}
+}
+
+func Test_ResponseFiles_TerminatorInFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeResponseFile(t, dir, "exec.args", "--verbose -- ls")
+
+	got, terminated, err := expandResponseFiles([]string{"exec", "@" + path, "@" + path}, "", 0)
+	assertNoError(t, err)
+	assertEqual(t, true, terminated)
+	assertEqual(t, "exec|--verbose|--|ls|@"+path, strings.Join(got, "|"), "args after a terminator in a file stay verbatim")
+}
+
+func Test_ResponseFiles_Errors(t *testing.T) {
+	dir := t.TempDir()
+	loop := writeResponseFile(t, dir, "loop.args", "@loop.args")
+	broken := writeResponseFile(t, dir, "broken.args", `--note "open`)
+
+	_, _, err := expandResponseFiles([]string{"@" + loop}, "", 0)
+	assertContains(t, err.Error(), "nested more than 8 deep", "a cycle hits the depth limit")
+
+	_, _, err = expandResponseFiles([]string{"@" + broken}, "", 0)
+	assertErrorIs(t, err, ErrUnterminatedQuote)
+
+	_, _, err = expandResponseFiles([]string{"@" + filepath.Join(dir, "missing.args")}, "", 0)
+	assertErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_App_ResponseFiles(t *testing.T) {
+	path := writeResponseFile(t, t.TempDir(), "run.args", "run --cwd /tmp")
+
+	ran := false
+	app := newTestApp(Config{Name: "app"}, GlobalFlags{})
+	app.Add("help", &recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+	app.Add("run", NewMockCommand(func() error { ran = true; return nil }))
+
+	err := app.Run([]string{"@" + path})
+	assertErrorIs(t, err, ErrShowingHelp, "expansion is off by default")
+	assertEqual(t, false, ran)
+
+	app.ResponseFiles(true)
+	assertNoError(t, app.Run([]string{"@" + path}))
+	assertEqual(t, true, ran)
+	assertEqual(t, "/tmp", app.globalFlags.Cwd, "globals inside a response file are parsed")
+}
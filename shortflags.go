@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/toaweme/structs"
+)
+
+// ErrInvalidFlag is returned for a flag the parser can't make sense of, such as a short-flag cluster
+// naming a flag that doesn't exist (see App.ShortFlagClustering). Main exits ExitUsage for it.
+var ErrInvalidFlag = errors.New("invalid flag")
+
+// ShortFlagClustering turns getopt-style short flags on or off, and returns the app for chaining.
+func (c *app) ShortFlagClustering(enabled bool) App {
+	c.clusterShorts = enabled
+
+	return c
+}
+
+// runFields returns the fields a run of osArgs parses against: the matched command's (the default's when
+// none matches), those its ancestors hand down (see Persistent), and the global flags.
+func (c *app) runFields(osArgs []string) []structs.Field {
+	fields, _ := structs.GetStructFields(c.globalFlags, nil, structs.DefaultEncodingTags)
+
+	command, commandArgs, _, err := c.matchCommandByArgs(osArgs)
+	if err != nil {
+		command = c.defaultCommand
+	}
+	if command == nil {
+		return fields
+	}
+	chain := c.commandChain(commandArgs)
+	if len(chain) == 0 {
+		chain = []Command[any]{command}
+	}
+	commandFields, _ := structs.GetStructFields(command.Options(), nil, structs.DefaultEncodingTags)
+	inherited, _ := inheritedChain(chain)
+
+	return slices.Concat(commandFields, inheritedFields(inherited), fields)
+}
+
+// expandShortFlags rewrites getopt-style short flags in args into the forms getCommandArgs reads:
+// a cluster of bool shorts "-abc" becomes "-a -b -c", and a value-taking short with its value attached,
+// "-p8080" (or last in a cluster, "-vp8080"), becomes "-p=8080". A token naming a flag as written
+// ("-o=file", or a multi-letter short or long flag after one dash) is left alone, as are tokens not
+// starting with a known short, and everything after a "--" terminator. A token that could mean either,
+// or a cluster naming an unknown short, fails with ErrInvalidFlag.
+func expandShortFlags(args []string, fields []structs.Field) ([]string, error) {
+	shorts := make(map[string]*structs.Field)
+	walkFields(fields, "", func(field structs.Field, _ string) {
+		if short := field.Tags[tagShort]; utf8.RuneCountInString(short) == 1 {
+			if _, seen := shorts[short]; !seen {
+				shorts[short] = &field
+			}
+		}
+	})
+
+	expanded := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == argsTerminator {
+			return append(expanded, args[i:]...), nil
+		}
+		body, ok := strings.CutPrefix(arg, optionPrefix)
+		if !ok || strings.HasPrefix(body, optionPrefix) || utf8.RuneCountInString(body) < 2 {
+			expanded = append(expanded, arg)
+			continue
+		}
+		first, _ := utf8.DecodeRuneInString(body)
+		if shorts[string(first)] == nil {
+			expanded = append(expanded, arg)
+			continue
+		}
+		if name, _ := splitKeyValue(body); matchField(fields, name) != nil || negatedFlag(fields, name) != "" {
+			if utf8.RuneCountInString(name) > 1 {
+				return nil, fmt.Errorf("%w: %s names a flag but also reads as the short flags -%s...; use --%s", ErrInvalidFlag, arg, string(first), name)
+			}
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		cluster, err := expandCluster(arg, body, shorts)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, cluster...)
+	}
+
+	return expanded, nil
+}
+
+// expandCluster splits the short flags of one token: bool and counter shorts one by one, until a value-taking
+// short, which takes the rest of the token (less a leading "=") as its value.
+func expandCluster(arg, body string, shorts map[string]*structs.Field) ([]string, error) {
+	var flags []string
+	for i, r := range body {
+		field := shorts[string(r)]
+		if field == nil {
+			return nil, fmt.Errorf("%w: unknown short flag -%s in %s", ErrInvalidFlag, string(r), arg)
+		}
+		if field.Type == "bool" || FieldCounter(*field) {
+			flags = append(flags, optionPrefix+string(r))
+			continue
+		}
+		value := strings.TrimPrefix(body[i+utf8.RuneLen(r):], "=")
+		if value == "" {
+			return append(flags, optionPrefix+string(r)), nil
+		}
+		return append(flags, optionPrefix+string(r)+"="+value), nil
+	}
+
+	return flags, nil
+}
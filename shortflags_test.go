@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/toaweme/structs"
+)
+
+type clusterFlags struct {
+	All     bool   `arg:"all" short:"a"`
+	Brief   bool   `arg:"brief" short:"b"`
+	Color   bool   `arg:"color" short:"c"`
+	Port    int    `arg:"port" short:"p"`
+	Output  string `arg:"output" short:"o"`
+	Verbose bool   `arg:"verbose" short:"v"`
+	Twice   bool   `arg:"twice" short:"vv"`
+	Target  string `arg:"0"`
+}
+
+func Test_expandShortFlags(t *testing.T) {
+	fields, err := structs.GetStructFields(&clusterFlags{}, nil, structs.DefaultEncodingTags)
+	assertNoError(t, err)
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+		err  string
+	}{
+		{name: "bool cluster", args: []string{"-abc"}, want: []string{"-a", "-b", "-c"}},
+		{name: "attached value", args: []string{"-p8080"}, want: []string{"-p=8080"}},
+		{name: "bools then a value", args: []string{"-abp8080"}, want: []string{"-a", "-b", "-p=8080"}},
+		{name: "value with equals", args: []string{"-ao=out.txt"}, want: []string{"-a", "-o=out.txt"}},
+		{name: "value from the next token", args: []string{"-ap", "80"}, want: []string{"-a", "-p", "80"}},
+		{name: "single short as written", args: []string{"-o=file", "-p", "1", "-a"}, want: []string{"-o=file", "-p", "1", "-a"}},
+		{name: "long flags and positionals untouched", args: []string{"--all", "--port=1", "target", "-"}, want: []string{"--all", "--port=1", "target", "-"}},
+		{name: "single-dash long flag starting with a short", args: []string{"-output=x"}, err: "-output=x names a flag but also reads as the short flags -o...; use --output"},
+		{name: "unknown first letter passes through", args: []string{"-xyz", "-1"}, want: []string{"-xyz", "-1"}},
+		{name: "not after the terminator", args: []string{"-ab", "--", "-abc"}, want: []string{"-a", "-b", "--", "-abc"}},
+		{name: "unknown short in a cluster", args: []string{"-abx"}, err: "unknown short flag -x in -abx"},
+		{name: "multi-letter short is ambiguous", args: []string{"-vv"}, err: "-vv names a flag but also reads as the short flags -v...; use --vv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandShortFlags(tt.args, fields)
+			if tt.err != "" {
+				assertErrorIs(t, err, ErrInvalidFlag)
+				assertContains(t, err.Error(), tt.err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, strings.Join(tt.want, " "), strings.Join(got, " "))
+		})
+	}
+}
+
+type clusterCommand struct {
+	BaseCommand[clusterFlags]
+}
+
+func (c *clusterCommand) Help() string                        { return "ls" }
+func (c *clusterCommand) Run(_ GlobalFlags, _ Unknowns) error { return nil }
+
+func Test_App_ShortFlagClustering(t *testing.T) {
+	newApp := func() (*app, *clusterCommand) {
+		cmd := &clusterCommand{BaseCommand: NewBaseCommand[clusterFlags]()}
+		app := newTestApp(Config{Name: "app"}, GlobalFlags{})
+		app.Add("help", &recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+		app.Add("ls", cmd)
+		return app, cmd
+	}
+
+	app, cmd := newApp()
+	assertNoError(t, app.RunContext(context.Background(), []string{"ls", "-ab"}))
+	assertEqual(t, false, cmd.Inputs.All, "off by default, -ab is one unknown flag")
+
+	app, cmd = newApp()
+	app.ShortFlagClustering(true)
+	assertNoError(t, app.RunContext(context.Background(), []string{"ls", "-acp8080", "dir"}))
+	assertEqual(t, true, cmd.Inputs.All)
+	assertEqual(t, false, cmd.Inputs.Brief)
+	assertEqual(t, true, cmd.Inputs.Color)
+	assertEqual(t, 8080, cmd.Inputs.Port)
+	assertEqual(t, "dir", cmd.Inputs.Target)
+
+	err := app.RunContext(context.Background(), []string{"ls", "-aq"})
+	assertErrorIs(t, err, ErrInvalidFlag)
+	assertEqual(t, ExitUsage, ExitCode(err))
+
+	err = app.RunContext(context.Background(), []string{"ls", "-ahV"})
+	assertErrorIs(t, err, ErrShowingVersion, "global shorts cluster too")
+}
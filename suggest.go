@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/toaweme/structs"
+)
+
+// maxSuggestionDistance caps the edit distance at which a known name is still offered as a correction.
+// The allowance grows with the typed name (a third of its length, at least one) up to this cap, so
+// short names don't match everything of similar length.
+const maxSuggestionDistance = 2
+
+// UnknownCommandError reports a command name that matched nothing at its level of the tree,
+// with the closest known names. It unwraps to ErrCommandNotFound, so errors.Is keeps working;
+// use errors.As to read the suggestions.
+type UnknownCommandError struct {
+	// Name is the token that did not match any command.
+	Name string
+	// Parent is the command path the name was looked up under (e.g. "db"), empty at the top level.
+	Parent string
+	// Suggestions are the closest command names, best first. Empty when nothing is close.
+	Suggestions []string
+}
+
+// Error renders the failure with its suggestions, e.g. "unknown command 'deplyo', did you mean 'deploy'?".
+func (e *UnknownCommandError) Error() string {
+	msg := fmt.Sprintf("unknown command '%s'", e.Name)
+	if e.Parent != "" {
+		msg += fmt.Sprintf(" for '%s'", e.Parent)
+	}
+
+	return msg + didYouMean(e.Suggestions, "")
+}
+
+// Unwrap returns ErrCommandNotFound.
+func (e *UnknownCommandError) Unwrap() error {
+	return ErrCommandNotFound
+}
+
+// UnknownFlagError reports a flag no command or global field declares, with the closest known flags.
+// Unknown flags are passed through to the command (see Unknowns), so the App prints this as a warning
+// rather than failing; it is only raised when a close match makes a typo likely.
+type UnknownFlagError struct {
+	// Name is the flag as typed, without dashes.
+	Name string
+	// Suggestions are the closest declared flag names (without dashes), best first.
+	Suggestions []string
+}
+
+// Error renders the warning, e.g. "unknown flag '--prot', did you mean '--port'?".
+func (e *UnknownFlagError) Error() string {
+	return fmt.Sprintf("unknown flag '--%s'", e.Name) + didYouMean(e.Suggestions, "--")
+}
+
+// didYouMean renders the ", did you mean ...?" tail for suggestions, each prefixed with prefix.
+func didYouMean(suggestions []string, prefix string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = "'" + prefix + s + "'"
+	}
+
+	return ", did you mean " + strings.Join(quoted, " or ") + "?"
+}
+
+// suggest returns the candidates close enough to name to be likely corrections - within the
+// length-scaled edit allowance, or extending name when it is at least three characters long -
+// closest first (ties alphabetical). name itself is never suggested.
+func suggest(name string, candidates []string) []string {
+	type match struct {
+		name     string
+		distance int
+	}
+
+	allowed := max(1, min(maxSuggestionDistance, len([]rune(name))/3))
+	var matches []match
+	seen := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		if candidate == "" || candidate == name || seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		d := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if d <= allowed || (len(name) >= 3 && strings.HasPrefix(candidate, name)) {
+			matches = append(matches, match{name: candidate, distance: d})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = m.name
+	}
+
+	return names
+}
+
+// editDistance is the optimal string alignment distance between a and b: the fewest single-rune
+// insertions, deletions, substitutions, or swaps of adjacent runes turning one into the other.
+// Counting a swap as one edit keeps the common "deplyo" -> "deploy" slip at distance 1.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
+
+// commandNames returns the names and aliases of the visible commands, the candidates for a command suggestion.
+// Hidden commands are never suggested, so a typo cannot reveal them.
+func commandNames(commands []Command[any]) []string {
+	var names []string
+	for _, cmd := range commands {
+		if CommandHidden(cmd) {
+			continue
+		}
+		names = append(names, cmd.Name(""))
+		names = append(names, CommandAliases(cmd)...)
+	}
+
+	return names
+}
+
+// unknownCommand builds the error for name failing to match under parent (the matched command path, if any),
+// suggesting from commands.
+func unknownCommand(name string, parent []string, commands []Command[any]) *UnknownCommandError {
+	return &UnknownCommandError{
+		Name:        name,
+		Parent:      strings.Join(parent, " "),
+		Suggestions: suggest(name, commandNames(commands)),
+	}
+}
+
+// flagNames returns the long flag names declared by fields, including nested fields by their dotted name,
+// the candidates for a flag suggestion. Shorts are left out: a one-letter typo has no meaningful neighbours.
+func flagNames(fields []structs.Field) []string {
+	var names []string
+	for _, field := range fields {
+		if arg := field.Tags[tagArg]; arg != "" && !isPositional(arg) {
+			names = append(names, arg)
+		}
+		if field.FQN != nil && field.FQN.Tags[tagArg] != "" {
+			names = append(names, field.FQN.Tags[tagArg])
+		}
+		names = append(names, flagNames(field.Fields)...)
+	}
+
+	return names
+}
+
+// unknownFlagWarnings returns an UnknownFlagError for each unknown option that is close to a declared flag
+// of the command (fields) or the globals, sorted by name. Options with no close match are genuine
+// pass-through and produce nothing.
+func (c *app) unknownFlagWarnings(unknownOptions map[string]any, fields []structs.Field) []*UnknownFlagError {
+	if len(unknownOptions) == 0 {
+		return nil
+	}
+	globalFields, _ := structs.GetStructFields(c.globalFlags, nil, structs.DefaultEncodingTags)
+	candidates := append(flagNames(fields), flagNames(globalFields)...)
+
+	names := make([]string, 0, len(unknownOptions))
+	for name := range unknownOptions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var warnings []*UnknownFlagError
+	for _, name := range names {
+		// a global flag reaches the command's unknowns too; it is known, not a typo.
+		if len(name) < 2 || matchField(globalFields, name) != nil {
+			continue
+		}
+		if suggestions := suggest(name, candidates); len(suggestions) > 0 {
+			warnings = append(warnings, &UnknownFlagError{Name: name, Suggestions: suggestions})
+		}
+	}
+
+	return warnings
+}
+
+// isPositional reports whether an arg tag is a positional index ("0", "1", ...) rather than a flag name.
+func isPositional(arg string) bool {
+	for _, r := range arg {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return arg != ""
+}
+
+// firstPositional returns the first non-flag token of osArgs as the global flags parse it
+// (so a global flag's value is not mistaken for it) and its index in osArgs, or "" and -1
+// when there is none.
+func (c *app) firstPositional(osArgs []string) (string, int) {
+	globalFields, _ := structs.GetStructFields(c.globalFlags, nil, structs.DefaultEncodingTags)
+	_, _, _, _, positions := scanCommandArgs(osArgs, globalFields)
+	if len(positions) == 0 {
+		return "", -1
+	}
+
+	return osArgs[positions[0]], positions[0]
+}
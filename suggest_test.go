@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_EditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"deploy", "deploy", 0},
+		{"deplyo", "deploy", 1},
+		{"prot", "port", 1},
+		{"stat", "status", 2},
+		{"", "abc", 3},
+		{"help", "beep", 2},
+	}
+	for _, tt := range tests {
+		assertEqual(t, tt.want, editDistance(tt.a, tt.b), tt.a+" -> "+tt.b)
+	}
+}
+
+func Test_Suggest(t *testing.T) {
+	candidates := []string{"deploy", "delete", "destroy", "status", "start", "help"}
+
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "swapped letters", in: "deplyo", want: []string{"deploy"}},
+		{name: "prefix", in: "sta", want: []string{"start", "status"}},
+		{name: "one edit away", in: "stats", want: []string{"status"}},
+		{name: "closest first", in: "stat", want: []string{"start", "status"}},
+		{name: "nothing close", in: "zebra", want: nil},
+		{name: "short names need a tight match", in: "hlp", want: []string{"help"}},
+		{name: "exact name is not suggested", in: "help", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := suggest(tt.in, candidates)
+			assertLen(t, got, len(tt.want))
+			for i := range tt.want {
+				assertEqual(t, tt.want[i], got[i])
+			}
+		})
+	}
+}
+
+func Test_UnknownCommandError_Message(t *testing.T) {
+	err := &UnknownCommandError{Name: "deplyo", Suggestions: []string{"deploy"}}
+	assertEqual(t, "unknown command 'deplyo', did you mean 'deploy'?", err.Error())
+
+	err = &UnknownCommandError{Name: "migrat", Parent: "db", Suggestions: []string{"migrate", "migrations"}}
+	assertEqual(t, "unknown command 'migrat' for 'db', did you mean 'migrate' or 'migrations'?", err.Error())
+
+	err = &UnknownCommandError{Name: "zebra"}
+	assertEqual(t, "unknown command 'zebra'", err.Error())
+	assertErrorIs(t, err, ErrCommandNotFound)
+
+	flagErr := &UnknownFlagError{Name: "prot", Suggestions: []string{"port"}}
+	assertEqual(t, "unknown flag '--prot', did you mean '--port'?", flagErr.Error())
+}
+
+func Test_App_UnknownCommand_Suggestions(t *testing.T) {
+	newApp := func(withDefault bool) App {
+		app := NewApp(Config{Name: "beep"}, GlobalFlags{})
+		app.Help(&recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+		deploy := NewMockCommand(func() error { return nil })
+		deploy.Alias("ship")
+		app.Add("deploy", deploy)
+		app.Add("db", NewMockCommand(func() error { return nil })).
+			Add("migrate", NewMockCommand(func() error { return nil }))
+		debug := NewMockCommand(func() error { return nil })
+		debug.Hide()
+		app.Add("debug", debug)
+		if withDefault {
+			app.Default(newPositionalCommand(func() error { return nil }))
+		}
+		return app
+	}
+
+	tests := []struct {
+		name        string
+		withDefault bool
+		args        []string
+		wantName    string
+		wantParent  string
+		wantSuggest []string
+	}{
+		{name: "top level", args: []string{"deplyo"}, wantName: "deplyo", wantSuggest: []string{"deploy"}},
+		{name: "alias", args: []string{"shp"}, wantName: "shp", wantSuggest: []string{"ship"}},
+		{name: "subcommand level", args: []string{"db", "migarte"}, wantName: "migarte", wantParent: "db", wantSuggest: []string{"migrate"}},
+		{name: "hidden never suggested", args: []string{"debg"}, wantName: "debg", wantSuggest: nil},
+		{name: "after global flag", args: []string{"--help-format", "json", "deplyo"}, wantName: "deplyo", wantSuggest: []string{"deploy"}},
+		{name: "default command fallback", withDefault: true, args: []string{"target", "deplyo"}, wantName: "deplyo", wantSuggest: []string{"deploy"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newApp(tt.withDefault).IO(IO{Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}).Run(tt.args)
+
+			assertErrorIs(t, err, ErrCommandNotFound)
+			assertErrorIs(t, err, ErrShowingHelp)
+			var unknown *UnknownCommandError
+			if !errors.As(err, &unknown) {
+				t.Fatalf("expected an *UnknownCommandError, got %T: %v", err, err)
+			}
+			assertEqual(t, tt.wantName, unknown.Name)
+			assertEqual(t, tt.wantParent, unknown.Parent)
+			assertLen(t, unknown.Suggestions, len(tt.wantSuggest))
+			for i := range tt.wantSuggest {
+				assertEqual(t, tt.wantSuggest[i], unknown.Suggestions[i])
+			}
+		})
+	}
+}
+
+// a bare invocation with no default is a help request, not an unknown command.
+func Test_App_NoArgs_NoUnknownCommandError(t *testing.T) {
+	app := NewApp(Config{Name: "beep"}, GlobalFlags{})
+	app.Help(&recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+	app.Add("deploy", NewMockCommand(func() error { return nil }))
+
+	err := app.Run([]string{})
+	assertErrorIs(t, err, ErrShowingHelp)
+	var unknown *UnknownCommandError
+	assertEqual(t, false, errors.As(err, &unknown))
+}
+
+type causeRecordingHelp struct {
+	BaseCommand[MockCommandConfig]
+	cause error
+}
+
+func (m *causeRecordingHelp) Help() string                        { return "help" }
+func (m *causeRecordingHelp) Run(_ GlobalFlags, _ Unknowns) error { return nil }
+func (m *causeRecordingHelp) RunContext(ctx context.Context, _ GlobalFlags, _ Unknowns) error {
+	m.cause = HelpCause(ctx)
+	return nil
+}
+
+func Test_App_HelpCause(t *testing.T) {
+	app := NewApp(Config{Name: "beep"}, GlobalFlags{})
+	rec := &causeRecordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()}
+	app.Help(rec)
+	app.Add("deploy", NewMockCommand(func() error { return nil }))
+
+	_ = app.Run([]string{"deplyo"})
+	var unknown *UnknownCommandError
+	if !errors.As(rec.cause, &unknown) {
+		t.Fatalf("expected the help command to see the unknown command, got %v", rec.cause)
+	}
+	assertEqual(t, "deploy", unknown.Suggestions[0])
+
+	rec.cause = nil
+	_ = app.Run([]string{"--help"})
+	assertNil(t, rec.cause, "an explicit --help has no cause")
+}
+
+func Test_App_UnknownFlag_Warning(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+	}{
+		{name: "typo of a command flag", args: []string{"deploy", "--numbr", "3"}, wantOut: "warning: unknown flag '--numbr', did you mean '--number'?\n"},
+		{name: "typo of a global flag", args: []string{"deploy", "--help-formt=json"}, wantOut: "warning: unknown flag '--help-formt', did you mean '--help-format'?\n"},
+		{name: "unrelated pass-through flag", args: []string{"deploy", "--upstream-token", "x"}, wantOut: ""},
+		{name: "global flag is not unknown", args: []string{"deploy", "-vv"}, wantOut: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			app := NewApp(Config{Name: "beep"}, GlobalFlags{})
+			app.Help(&recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+			app.Add("deploy", NewMockCommand(func() error {
+				ran = true
+				return nil
+			}))
+
+			var stderr bytes.Buffer
+			app.IO(IO{Out: &bytes.Buffer{}, Err: &stderr})
+
+			assertNoError(t, app.Run(tt.args))
+			assertEqual(t, tt.wantOut, stderr.String())
+			assertEqual(t, true, ran, "unknown flags still pass through")
+		})
+	}
+}
@@ -1,5 +1,10 @@
 package cli
 
+import (
+	"context"
+	"io"
+)
+
 // Command is the interface every CLI command must implement.
 // T is the config struct type whose fields define the command's flags and positional args.
 type Command[T any] interface {
@@ -33,6 +38,22 @@ type Command[T any] interface {
 	Flags() map[string][]string
 }
 
+// ContextRunner is the optional context-receiving variant of Command.Run. When the matched command
+// implements it, the App calls RunContext instead of Run, passing the run context (cancelled on the
+// first SIGINT/SIGTERM under App.Run). Commands built on BaseCommand can instead keep a plain Run and
+// read the same context through BaseCommand.Context.
+type ContextRunner interface {
+	// RunContext executes the command logic under ctx, with parsed global options and unknown args.
+	RunContext(ctx context.Context, options GlobalFlags, unknowns Unknowns) error
+}
+
+// ErrorDisplayer is the optional interface a help command implements to render errors in the active
+// --help-format. App.Main writes the errors it reports through the registered help command's, when it has one.
+type ErrorDisplayer interface {
+	// DisplayError writes err to w in format, a --help-format value ("" for the default).
+	DisplayError(w io.Writer, err error, format string)
+}
+
 // Resolver contributes values to a command's Options() before Run.
 // Resolvers compose like middleware: the framework registers any number on the App,
 // then runs them in order, threading each one's output into the next.
@@ -55,6 +76,9 @@ type Config struct {
 	Name string `json:"name" yaml:"name"`
 	// Version is the semantic version string printed by the built-in --version / -V flag.
 	Version string `json:"version" yaml:"version"`
+	// EnvPrefix, when set (e.g. "MYAPP"), binds every flag without an env tag to MYAPP_<FLAG>:
+	// --database.host reads MYAPP_DATABASE_HOST (see EnvName). Tag a field env:"-" to keep it unbound.
+	EnvPrefix string `json:"envPrefix,omitempty" yaml:"envPrefix,omitempty"`
 }
 
 // OutputCodec renders help output for a custom --help-format value.
@@ -92,6 +116,9 @@ type GlobalFlags struct {
 	// Short is capital -V (clap-style) so lowercase -v stays free for the author's own "verbose" flag,
 	// which is what users overwhelmingly expect -v to mean.
 	Version bool `arg:"version" short:"V" env:"VERSION" help:"Show version"`
+	// NoInput never prompts for a required input left empty (see App.Prompter), so the run fails validation
+	// as it would without a terminal. Long-only, like --cwd.
+	NoInput bool `arg:"no-input" env:"NO_INPUT" help:"Never prompt for missing required inputs"`
 }
 
 // Unknowns holds arguments and options that were not matched to any defined field.
@@ -101,4 +128,7 @@ type Unknowns struct {
 	Args []string
 	// Options are key-value flags not defined in the command's config struct.
 	Options map[string]any
+	// Passthrough are the arguments after a "--" terminator, verbatim and in order: never parsed as
+	// flags and never filling a positional slot. Help shows them for commands that AcceptPassthrough.
+	Passthrough []string
 }
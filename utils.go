@@ -31,7 +31,7 @@ func mapStructToOptions(structure any, vars map[string]any, skipValidate ...stri
 	}
 
 	if len(errors) > 0 {
-		return fmt.Errorf("validation failed: %v", errors)
+		return newValidationError(structure, "", errors, validateVars)
 	}
 
 	err = manager.Set(vars)
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/toaweme/structs"
+)
+
+// redacted stands in for the value of a secret field in validation errors.
+const redacted = "[redacted]"
+
+// FieldError is one failed validation rule on one field, spelled the ways a user can set it.
+type FieldError struct {
+	// Field is the Go field name, dotted for a nested field (e.g. "Database.Host").
+	Field string `json:"field" yaml:"field" toml:"field"`
+	// Flag is the long flag ("--port"), or "" for a positional argument or a field without one.
+	Flag string `json:"flag,omitempty" yaml:"flag,omitempty" toml:"flag,omitempty"`
+	// Short is the short flag ("-p"), when the field has one.
+	Short string `json:"short,omitempty" yaml:"short,omitempty" toml:"short,omitempty"`
+	// Position is the zero-based index of a positional argument, or -1 for a flag.
+	Position int `json:"position" yaml:"position" toml:"position"`
+	// Env is the environment variable bound to the field, when it has one.
+	Env string `json:"env,omitempty" yaml:"env,omitempty" toml:"env,omitempty"`
+	// Rule is the name of the failed rule ("required", "oneof").
+	Rule string `json:"rule" yaml:"rule" toml:"rule"`
+	// Value is the offending value, "" when unset and redacted for a field tagged secret:"true".
+	Value string `json:"value,omitempty" yaml:"value,omitempty" toml:"value,omitempty"`
+	// Message describes the failure in words ("is required", "must be one of dev, prod").
+	Message string `json:"message" yaml:"message" toml:"message"`
+}
+
+// Name is how the field is shown to a user: its long flag, its positional argument, its env var,
+// or as a last resort its Go name.
+func (e FieldError) Name() string {
+	switch {
+	case e.Flag != "":
+		return e.Flag
+	case e.Position >= 0:
+		return "argument " + strconv.Itoa(e.Position+1)
+	case e.Env != "":
+		return "$" + e.Env
+	default:
+		return e.Field
+	}
+}
+
+// Error renders the failure as "--port (-p, $PORT) must be one of 80, 443, got \"8080\"".
+func (e FieldError) Error() string {
+	var alternates []string
+	if e.Short != "" {
+		alternates = append(alternates, e.Short)
+	}
+	if e.Env != "" && (e.Flag != "" || e.Position >= 0) {
+		alternates = append(alternates, "$"+e.Env)
+	}
+
+	var b strings.Builder
+	b.WriteString(e.Name())
+	if len(alternates) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(alternates, ", "))
+	}
+	b.WriteString(" " + e.Message)
+	if e.Value != "" {
+		fmt.Fprintf(&b, ", got %q", e.Value)
+	}
+
+	return b.String()
+}
+
+// ValidationError is returned when a command's inputs fail their struct rules. It lists every
+// failure, in field order, and matches ErrValidationFailed under errors.Is; use errors.As to reach
+// the individual FieldErrors.
+type ValidationError struct {
+	Fields []FieldError `json:"fields" yaml:"fields" toml:"fields"`
+}
+
+// Error renders the failures on one line: "validation failed: --port is required; --mode must be ...".
+func (e *ValidationError) Error() string {
+	failures := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		failures = append(failures, field.Error())
+	}
+
+	return ErrValidationFailed.Error() + ": " + strings.Join(failures, "; ")
+}
+
+// Unwrap returns ErrValidationFailed, so errors.Is keeps matching it.
+func (e *ValidationError) Unwrap() error { return ErrValidationFailed }
+
+// newValidationError builds the ValidationError for the failures structs reported against options,
+// keyed by field and listing the failed rules, given the inputs validated (flags and env) and the
+// env prefix the fields are bound through. Failures are ordered as the fields are declared.
+func newValidationError(options any, envPrefix string, failures map[string][]string, inputs map[string]any) *ValidationError {
+	fields, _ := structs.GetStructFields(options, nil, structs.DefaultEncodingTags)
+	applyEnvNames(fields, envPrefix)
+
+	verr := &ValidationError{}
+	matched := make(map[string]bool, len(failures))
+	walkFields(fields, "", func(field structs.Field, path string) {
+		for _, key := range []string{path, field.Name, fieldTag(field, tagArg), fieldTag(field, "env")} {
+			rules, ok := failures[key]
+			if key == "" || !ok || matched[key] {
+				continue
+			}
+			matched[key] = true
+			for _, rule := range rules {
+				verr.Fields = append(verr.Fields, newFieldError(field, path, rule, inputs))
+			}
+			return
+		}
+	})
+
+	// failures on keys no field answers to are still reported, by key.
+	rest := make([]string, 0)
+	for key := range failures {
+		if !matched[key] {
+			rest = append(rest, key)
+		}
+	}
+	slices.Sort(rest)
+	for _, key := range rest {
+		for _, rule := range failures[key] {
+			verr.Fields = append(verr.Fields, FieldError{Field: key, Position: -1, Rule: rule, Message: "failed rule " + rule})
+		}
+	}
+
+	return verr
+}
+
+// walkFields calls fn for every leaf field, depth first, with its dotted Go path. A struct decoded from
+// text (see Value), such as url.URL, is a leaf.
+func walkFields(fields []structs.Field, prefix string, fn func(field structs.Field, path string)) {
+	for _, field := range fields {
+		path := prefix + field.Name
+		if len(field.Fields) > 0 && !(field.Value.IsValid() && isTextType(field.Value.Type())) {
+			walkFields(field.Fields, path+".", fn)
+			continue
+		}
+		fn(field, path)
+	}
+}
+
+// fieldTag returns the field's tag, preferring the fully qualified one of a nested field.
+func fieldTag(field structs.Field, tag string) string {
+	if field.FQN != nil && field.FQN.Tags[tag] != "" {
+		return field.FQN.Tags[tag]
+	}
+
+	return field.Tags[tag]
+}
+
+// newFieldError describes the failed rule (as structs reports it) on field.
+func newFieldError(field structs.Field, path, failed string, inputs map[string]any) FieldError {
+	name, _, _ := strings.Cut(failed, ":")
+	fe := fieldError(field, path, name)
+
+	var args []string
+	for _, rule := range field.Rules {
+		if rule.Name == name {
+			args = rule.Args
+		}
+	}
+	switch name {
+	case "required":
+		fe.Message = "is required"
+	case "oneof":
+		fe.Message = "must be one of " + strings.Join(args, ", ")
+	default:
+		fe.Message = "failed rule " + name
+		if len(args) > 0 {
+			fe.Message += ":" + strings.Join(args, ",")
+		}
+	}
+	if name != "required" {
+		fe.Value = fieldValue(field, inputs)
+	}
+
+	return fe
+}
+
+// fieldError is a FieldError for rule on field, naming it every way a user can set it; the caller fills
+// in the message and value.
+func fieldError(field structs.Field, path, rule string) FieldError {
+	fe := FieldError{Field: path, Position: -1, Env: fieldTag(field, "env"), Rule: rule}
+	if arg := fieldTag(field, tagArg); isPositional(arg) {
+		fe.Position, _ = strconv.Atoi(arg)
+	} else if arg != "" {
+		fe.Flag = "--" + arg
+	}
+	if short := field.Tags["short"]; short != "" {
+		fe.Short = "-" + short
+	}
+
+	return fe
+}
+
+// fieldInput returns the input given for field in inputs, looked up by its flag, short flag, and env
+// var names, and whether there is one.
+func fieldInput(field structs.Field, inputs map[string]any) (any, bool) {
+	for _, key := range []string{fieldTag(field, tagArg), field.Tags[tagShort], fieldTag(field, "env")} {
+		if v, ok := inputs[key]; ok && key != "" {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// fieldValue renders the value given for field (its input, else its current value) for an error message,
+// redacting a secret field's.
+func fieldValue(field structs.Field, inputs map[string]any) string {
+	input, _ := fieldInput(field, inputs)
+	if input == nil && (!field.Value.IsValid() || field.Value.IsZero()) {
+		return ""
+	}
+	if secret, ok := field.Tags["secret"]; ok && truthy(secret) {
+		return redacted
+	}
+	if input != nil {
+		if multi, ok := input.(structs.MultiValue); ok {
+			return strings.Join(multi, ",")
+		}
+		return fmt.Sprint(input)
+	}
+	if field.Value.Kind() == reflect.Slice {
+		parts := make([]string, 0, field.Value.Len())
+		for i := 0; i < field.Value.Len(); i++ {
+			parts = append(parts, fmt.Sprint(field.Value.Index(i).Interface()))
+		}
+		return strings.Join(parts, ",")
+	}
+
+	return fmt.Sprint(field.Value.Interface())
+}
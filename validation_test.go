@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type validationConfig struct {
+	Target string `arg:"0" help:"Where to deploy" rules:"required"`
+	Port   string `arg:"port" short:"p" env:"VALIDATION_PORT" help:"Port" rules:"required"`
+	Mode   string `arg:"mode" help:"Mode" rules:"oneof:dev,prod"`
+	Token  string `arg:"token" env:"VALIDATION_TOKEN" secret:"true" rules:"oneof:abc,def"`
+	DB     struct {
+		Host string `arg:"host" env:"HOST" rules:"required"`
+	} `arg:"db" env:"VALIDATION_DB"`
+}
+
+func Test_ValidationError_Fields(t *testing.T) {
+	app := newTestApp(Config{Name: "app"}, GlobalFlags{})
+	app.Add("help", &recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+	app.Add("deploy", &validatingCommand{BaseCommand: NewBaseCommand[validationConfig]()})
+
+	err := app.Run([]string{"deploy", "--mode", "fast", "--token", "hunter2"})
+	assertErrorIs(t, err, ErrValidationFailed)
+
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected a *ValidationError, got %T: %v", err, err)
+	}
+	assertLen(t, invalid.Fields, 5, "every failed rule is listed")
+
+	target, port, mode, token, host := invalid.Fields[0], invalid.Fields[1], invalid.Fields[2], invalid.Fields[3], invalid.Fields[4]
+	assertEqual(t, 0, target.Position)
+	assertEqual(t, "argument 1 is required", target.Error())
+
+	assertEqual(t, "--port", port.Flag)
+	assertEqual(t, "-p", port.Short)
+	assertEqual(t, "VALIDATION_PORT", port.Env)
+	assertEqual(t, "required", port.Rule)
+	assertEqual(t, "--port (-p, $VALIDATION_PORT) is required", port.Error())
+
+	assertEqual(t, "oneof", mode.Rule)
+	assertEqual(t, `--mode must be one of dev, prod, got "fast"`, mode.Error())
+
+	assertEqual(t, redacted, token.Value, "a secret value is redacted")
+	assertEqual(t, false, strings.Contains(err.Error(), "hunter2"), "the secret never reaches the message")
+
+	assertEqual(t, "DB.Host", host.Field)
+	assertEqual(t, "--db.host", host.Flag)
+	assertEqual(t, "VALIDATION_DB_HOST", host.Env)
+}
+
+type validatingCommand struct {
+	BaseCommand[validationConfig]
+}
+
+func (c *validatingCommand) Help() string                        { return "deploy" }
+func (c *validatingCommand) Run(_ GlobalFlags, _ Unknowns) error { return nil }
@@ -0,0 +1,359 @@
+package cli
+
+import (
+	"encoding"
+	"fmt"
+	"maps"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/toaweme/structs"
+)
+
+// Value is a config field type that parses its own text: flags, env vars, config values, and `default:`
+// tags all reach it through Set. A field implementing encoding.TextUnmarshaler is decoded the same way.
+type Value interface {
+	Set(text string) error
+	String() string
+}
+
+// Size is a byte count read from a human size: "512", "64k", "10MiB", "1.5GB". Binary units
+// (KiB, MiB, ... and the bare K, M, ...) count in 1024s, decimal ones (kB, MB, ...) in 1000s.
+type Size int64
+
+// sizeUnits are the suffixes ParseSize accepts, matched case-insensitively, with their byte counts.
+var sizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kib": 1 << 10,
+	"kb":  1e3,
+	"m":   1 << 20,
+	"mib": 1 << 20,
+	"mb":  1e6,
+	"g":   1 << 30,
+	"gib": 1 << 30,
+	"gb":  1e9,
+	"t":   1 << 40,
+	"tib": 1 << 40,
+	"tb":  1e12,
+	"p":   1 << 50,
+	"pib": 1 << 50,
+	"pb":  1e15,
+}
+
+// binaryUnits are the units Size.String writes, largest first.
+var binaryUnits = []string{"PiB", "TiB", "GiB", "MiB", "KiB"}
+
+// ParseSize parses a human byte size such as "10MiB" (see Size): a plain decimal number, never negative,
+// and an optional unit.
+func ParseSize(text string) (Size, error) {
+	text = strings.TrimSpace(text)
+	number := strings.TrimRightFunc(text, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	unit, ok := sizeUnits[strings.ToLower(strings.TrimSpace(text[len(number):]))]
+	// only digits and a point, so no sign ("-5"), exponent, or hex form gets through to ParseFloat
+	if !ok || number == "" || strings.TrimLeft(number, "0123456789.") != "" {
+		return 0, fmt.Errorf("invalid size %q", text)
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n*unit > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q", text)
+	}
+
+	return Size(n * unit), nil
+}
+
+// Set parses text as a human size, for Size to be a Value.
+func (s *Size) Set(text string) error {
+	n, err := ParseSize(text)
+	if err != nil {
+		return err
+	}
+	*s = n
+
+	return nil
+}
+
+// String writes the size in the largest binary unit dividing it exactly ("10MiB"), in bytes otherwise.
+func (s Size) String() string {
+	for _, unit := range binaryUnits {
+		n := int64(sizeUnits[strings.ToLower(unit)])
+		if s != 0 && int64(s)%n == 0 {
+			return strconv.FormatInt(int64(s)/n, 10) + unit
+		}
+	}
+
+	return strconv.FormatInt(int64(s), 10)
+}
+
+var (
+	valueType           = reflect.TypeOf((*Value)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	urlType             = reflect.TypeOf(url.URL{})
+)
+
+// dateLayout is the date-only form a time.Time field accepts besides RFC 3339.
+const dateLayout = "2006-01-02"
+
+// isTextType reports whether fields of type t are decoded from text by decodeText rather than by structs:
+// durations, URLs, and types implementing Value or encoding.TextUnmarshaler, or pointers to them.
+func isTextType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case durationType, timeType, urlType:
+		return true
+	}
+	ptr := reflect.PointerTo(t)
+
+	return ptr.Implements(valueType) || ptr.Implements(textUnmarshalerType)
+}
+
+// isDecodedType reports whether fields of type t are decoded by the app rather than by structs: text
+// types (see isTextType) and maps (see isMapType).
+func isDecodedType(t reflect.Type) bool {
+	return isTextType(t) || isMapType(t)
+}
+
+// decodeText parses text into v, a settable value of a type isTextType accepts, allocating a nil pointer.
+func decodeText(v reflect.Value, text string) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeText(elem.Elem(), text); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(strings.TrimSpace(text))
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case timeType:
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			var dateErr error
+			if t, dateErr = time.Parse(dateLayout, strings.TrimSpace(text)); dateErr != nil {
+				return err
+			}
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case urlType:
+		u, err := url.Parse(strings.TrimSpace(text))
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch target := v.Addr().Interface().(type) {
+	case Value:
+		return target.Set(text)
+	case encoding.TextUnmarshaler:
+		return target.UnmarshalText([]byte(text))
+	}
+
+	return fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// typeNames are the names help shows for the built-in text types.
+var typeNames = map[reflect.Type]string{
+	durationType:                    "duration",
+	timeType:                        "time",
+	urlType:                         "url",
+	reflect.TypeOf(Size(0)):         "size",
+	reflect.TypeOf(net.IP{}):        "ip",
+	reflect.TypeOf(netip.Addr{}):    "ip",
+	reflect.TypeOf(regexp.Regexp{}): "regexp",
+}
+
+// FieldTypeName is the name help shows for the type of a field decoded from text (see Value): "duration",
+// "size", "time", "ip", "url", or "regexp" for the built-in ones, the lowercased type name for any other,
+// and "" for a field structs decodes, whose reflect type help shows as it is.
+func FieldTypeName(field structs.Field) string {
+	if !field.Value.IsValid() || !isTextType(field.Value.Type()) {
+		return ""
+	}
+	t := field.Value.Type()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+
+	return strings.ToLower(t.Name())
+}
+
+// decodeValues sets the fields that decode from text (see Value) or are maps (see decodeMap) from
+// layer. A field layer has no value for takes its `default:` tag while still zero, except when layer
+// is the flags. It returns the rest of layer for structs to apply. A value that fails to parse is a
+// *ValidationError naming the flag and where the value came from.
+func (c *app) decodeValues(fields []structs.Field, layer map[string]any, isFlags bool) (map[string]any, error) {
+	rest := maps.Clone(layer)
+	if rest == nil {
+		rest = map[string]any{}
+	}
+
+	verr := &ValidationError{}
+	var walk func(fields []structs.Field, prefix string, sub map[string]any)
+	walk = func(fields []structs.Field, prefix string, sub map[string]any) {
+		for _, field := range fields {
+			path := prefix + field.Name
+			if !field.Value.IsValid() || !isDecodedType(field.Value.Type()) {
+				if len(field.Fields) > 0 {
+					walk(field.Fields, path+".", nestedLayer(field, sub))
+				}
+				continue
+			}
+
+			raw, key, ok := takeValue(field, rest, sub)
+			source := c.valueSource(field, key, raw, isFlags)
+			if !ok {
+				if isFlags || field.Tags["default"] == "" || !field.Value.IsZero() {
+					continue
+				}
+				raw, source = field.Tags["default"], "default"
+			}
+			if err := setText(field, raw); err != nil {
+				verr.Fields = append(verr.Fields, valueError(field, path, source, err))
+			}
+		}
+	}
+	walk(fields, "", rest)
+
+	if len(verr.Fields) > 0 {
+		return nil, verr
+	}
+
+	return rest, nil
+}
+
+// nestedLayer is the map sub holds for the nested struct field, copied into sub so values can be taken
+// from it without touching the caller's; nil when there is none.
+func nestedLayer(field structs.Field, sub map[string]any) map[string]any {
+	for _, tag := range defaultTags {
+		key := field.Tags[tag]
+		if key == "" {
+			continue
+		}
+		if nested, ok := sub[key].(map[string]any); ok {
+			nested = maps.Clone(nested)
+			sub[key] = nested
+			return nested
+		}
+	}
+
+	return nil
+}
+
+// takeValue removes every value top (by the field's full names) or sub (the nested map of its parent,
+// by its own names) holds for field, returning the one structs would have picked and its key. A flag
+// repeated under both its names ("--label a=1 -l b=2") gives every occurrence.
+func takeValue(field structs.Field, top, sub map[string]any) (any, string, bool) {
+	var value any
+	found := ""
+	take := func(layer map[string]any, key string) {
+		if v, ok := layer[key]; ok && key != "" {
+			if found == "" {
+				value, found = v, key
+			} else if multi, ok := value.(structs.MultiValue); ok {
+				if more, ok := v.(structs.MultiValue); ok {
+					value = append(slices.Clone(multi), more...)
+				}
+			}
+			delete(layer, key)
+		}
+	}
+	for _, tag := range defaultTags {
+		take(top, fieldTag(field, tag))
+	}
+	if field.FQN != nil && sub != nil {
+		for _, tag := range defaultTags {
+			take(sub, field.Tags[tag])
+		}
+	}
+
+	return value, found, found != ""
+}
+
+// valueSource names where the value under key came from, for errors: the flag as typed ("--timeout",
+// "-t"), the env var ("$TIMEOUT"), or "config".
+func (c *app) valueSource(field structs.Field, key string, raw any, isFlags bool) string {
+	if isFlags {
+		if key == field.Tags[tagShort] {
+			return "-" + key
+		}
+		return "--" + key
+	}
+	if env := fieldTag(field, "env"); env != "" {
+		if key == env {
+			return "$" + env
+		}
+		if value, ok := c.stdio().Env.LookupEnv(env); ok && value == fmt.Sprint(raw) {
+			return "$" + env
+		}
+	}
+
+	return "config"
+}
+
+// setText sets field from raw, a layer's value: parsed from its text (the last of a repeated flag's),
+// or assigned as it is when it already has the field's type. Empty text leaves the field unset. A map
+// field takes every entry raw holds (see decodeMap).
+func setText(field structs.Field, raw any) error {
+	if v := reflect.ValueOf(raw); v.IsValid() && v.Type().AssignableTo(field.Value.Type()) {
+		field.Value.Set(v)
+		return nil
+	}
+	if isMapType(field.Value.Type()) {
+		if raw == nil || raw == "" {
+			return nil
+		}
+		return decodeMap(field.Value, raw, fieldSep(field))
+	}
+	text := fmt.Sprint(raw)
+	if multi, ok := raw.(structs.MultiValue); ok && len(multi) > 0 {
+		text = multi[len(multi)-1]
+	}
+	if text == "" {
+		return nil
+	}
+
+	return decodeText(field.Value, text)
+}
+
+// valueError is the FieldError for a value of field, from source, its type failed to parse.
+func valueError(field structs.Field, path, source string, err error) FieldError {
+	fe := fieldError(field, path, "type")
+	name := FieldTypeName(field)
+	if name == "" {
+		name = field.Value.Type().String()
+	}
+	fe.Message = fmt.Sprintf("is not a valid %s (from %s)", name, source)
+	if secret, ok := field.Tags["secret"]; !ok || !truthy(secret) {
+		fe.Message += ": " + err.Error()
+	}
+
+	return fe
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// level is a Value: it parses its own text.
+type level int
+
+func (l *level) Set(text string) error {
+	switch strings.ToLower(text) {
+	case "debug":
+		*l = 1
+	case "info":
+		*l = 2
+	default:
+		return errors.New("unknown level " + text)
+	}
+	return nil
+}
+
+func (l level) String() string { return [...]string{"", "debug", "info"}[l] }
+
+type valuesConfig struct {
+	Timeout  time.Duration  `arg:"timeout" env:"VALUES_TIMEOUT" default:"30s"`
+	Since    time.Time      `arg:"since"`
+	Limit    Size           `arg:"limit" short:"l"`
+	Bind     net.IP         `arg:"bind"`
+	Endpoint *url.URL       `arg:"endpoint"`
+	Match    *regexp.Regexp `arg:"match"`
+	Level    level          `arg:"level"`
+	Token    Size           `arg:"token" secret:"true"`
+	Name     string         `arg:"name"`
+}
+
+type valuesCommand struct {
+	BaseCommand[valuesConfig]
+}
+
+func (c *valuesCommand) Help() string                        { return "serve" }
+func (c *valuesCommand) Run(_ GlobalFlags, _ Unknowns) error { return nil }
+
+func runValues(t *testing.T, env map[string]string, args ...string) (*valuesCommand, error) {
+	t.Helper()
+	cmd := &valuesCommand{BaseCommand: NewBaseCommand[valuesConfig]()}
+	app := newTestApp(Config{}, GlobalFlags{})
+	app.IO(IO{Env: MapEnv(env)})
+	app.Add("help", &recordingHelp{BaseCommand: NewBaseCommand[MockCommandConfig]()})
+	app.Add("serve", cmd)
+
+	return cmd, app.Run(append([]string{"serve"}, args...))
+}
+
+func Test_App_TextValues(t *testing.T) {
+	cmd, err := runValues(t, nil,
+		"--since", "2024-05-01", "-l", "10MiB", "--bind", "10.0.0.1", "--endpoint", "https://example.com/api",
+		"--match", "^v[0-9]+$", "--level", "info", "--name", "x",
+	)
+	assertNoError(t, err)
+
+	in := cmd.Inputs
+	assertEqual(t, 30*time.Second, in.Timeout, "the default is parsed")
+	assertEqual(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), in.Since)
+	assertEqual(t, Size(10<<20), in.Limit)
+	assertEqual(t, "10.0.0.1", in.Bind.String())
+	assertEqual(t, "example.com", in.Endpoint.Host)
+	assertEqual(t, true, in.Match.MatchString("v12"))
+	assertEqual(t, level(2), in.Level)
+	assertEqual(t, "x", in.Name, "other fields still go through structs")
+}
+
+func Test_App_TextValues_Layers(t *testing.T) {
+	cmd, err := runValues(t, map[string]string{"VALUES_TIMEOUT": "1m"})
+	assertNoError(t, err)
+	assertEqual(t, time.Minute, cmd.Inputs.Timeout, "env beats the default")
+
+	cmd, err = runValues(t, map[string]string{"VALUES_TIMEOUT": "1m"}, "--timeout=5s")
+	assertNoError(t, err)
+	assertEqual(t, 5*time.Second, cmd.Inputs.Timeout, "a flag beats env")
+}
+
+func Test_App_TextValues_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{name: "flag", args: []string{"--timeout", "soon"}, want: `--timeout ($VALUES_TIMEOUT) is not a valid duration (from --timeout): time: invalid duration "soon"`},
+		{name: "short flag", args: []string{"-l", "lots"}, want: `--limit (-l) is not a valid size (from -l): invalid size "lots"`},
+		{name: "env", env: map[string]string{"VALUES_TIMEOUT": "soon"}, want: `is not a valid duration (from $VALUES_TIMEOUT)`},
+		{name: "value type", args: []string{"--level", "loud"}, want: `--level is not a valid level (from --level): unknown level loud`},
+		{name: "secret", args: []string{"--token", "hunter2"}, want: `--token is not a valid size (from --token)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runValues(t, tt.env, tt.args...)
+			assertErrorIs(t, err, ErrValidationFailed)
+			assertContains(t, err.Error(), tt.want)
+			assertEqual(t, false, strings.Contains(err.Error(), "hunter2"), "a secret value stays out of the message")
+		})
+	}
+}
+
+func Test_ParseSize(t *testing.T) {
+	tests := []struct {
+		text string
+		want Size
+		err  bool
+	}{
+		{text: "512", want: 512},
+		{text: "512B", want: 512},
+		{text: "64k", want: 64 << 10},
+		{text: "10MiB", want: 10 << 20},
+		{text: "10mb", want: 10_000_000},
+		{text: "1.5GiB", want: 3 << 29},
+		{text: " 2 TB ", want: 2e12},
+		{text: "", err: true},
+		{text: "MiB", err: true},
+		{text: "10XB", err: true},
+		{text: "1.2.3k", err: true},
+		{text: "-5", err: true},
+		{text: "-10MiB", err: true},
+		{text: "+5", err: true},
+		{text: "1e3", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseSize(tt.text)
+			if tt.err {
+				assertError(t, err)
+				return
+			}
+			assertNoError(t, err)
+			assertEqual(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Size_String(t *testing.T) {
+	assertEqual(t, "0", Size(0).String())
+	assertEqual(t, "1500", Size(1500).String())
+	assertEqual(t, "10MiB", Size(10<<20).String())
+	assertEqual(t, "1536MiB", Size(3<<29).String())
+}
@@ -10,28 +10,17 @@ package cli
 //		Name string `arg:"name"`
 //	}
 //
-// The three flags are independent switches matched as their own short tokens
-// (-v, -vv, -vvv), not a true repeat-counter. Use Level to read the highest one
-// the user passed rather than touching the bool fields directly.
+// The flag is a counter (see FieldCounter): -v, -vv, -v -v, and --verbose --verbose
+// each add to it, and --verbose=2 sets it. Use Level to read it rather than
+// touching Count directly.
 type Verbosity struct {
-	V1 bool `short:"v" help:"Verbose output (-v)"`
-	V2 bool `short:"vv" help:"More verbose output (-vv)"`
-	V3 bool `short:"vvv" help:"Most verbose output (-vvv)"`
+	Count int `arg:"verbose" short:"v" count:"true" help:"Verbose output, repeat for more (-vv, -vvv)"`
 }
 
-// Level returns the verbosity the user selected: 0 when no flag was passed, up
-// to 3 for -vvv. The highest flag wins, so -vvv reports 3 even if -v is also set.
+// Level returns the verbosity the user selected: 0 when no flag was passed,
+// one more for every -v, so -vvv reports 3.
 func (v Verbosity) Level() int {
-	switch {
-	case v.V3:
-		return 3
-	case v.V2:
-		return 2
-	case v.V1:
-		return 1
-	default:
-		return 0
-	}
+	return max(v.Count, 0)
 }
 
 // Verbose reports whether any verbosity flag was passed (Level > 0).
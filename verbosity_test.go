@@ -13,10 +13,10 @@ func Test_Verbosity_Level(t *testing.T) {
 		want int
 	}{
 		{name: "none", v: Verbosity{}, want: 0},
-		{name: "v", v: Verbosity{V1: true}, want: 1},
-		{name: "vv", v: Verbosity{V2: true}, want: 2},
-		{name: "vvv", v: Verbosity{V3: true}, want: 3},
-		{name: "highest wins", v: Verbosity{V1: true, V3: true}, want: 3},
+		{name: "v", v: Verbosity{Count: 1}, want: 1},
+		{name: "vv", v: Verbosity{Count: 2}, want: 2},
+		{name: "vvv", v: Verbosity{Count: 3}, want: 3},
+		{name: "negative reads as none", v: Verbosity{Count: -1}, want: 0},
 	}
 
 	for _, tt := range tests {
@@ -28,9 +28,8 @@ func Test_Verbosity_Level(t *testing.T) {
 	}
 }
 
-// Test_Verbosity_Parsing proves the embeddable flags resolve onto a command's
-// input struct end-to-end: -v/-vv/-vvv each match their own short token and set
-// the matching bool, which Level then reads back.
+// Test_Verbosity_Parsing proves the embeddable counter resolves onto a command's
+// input struct end-to-end: every spelling of a repeat adds up, which Level then reads back.
 func Test_Verbosity_Parsing(t *testing.T) {
 	type inputs struct {
 		Verbosity
@@ -46,6 +45,12 @@ func Test_Verbosity_Parsing(t *testing.T) {
 		{name: "-v", args: []string{"-v"}, want: 1},
 		{name: "-vv", args: []string{"-vv"}, want: 2},
 		{name: "-vvv", args: []string{"-vvv"}, want: 3},
+		{name: "-v -v -v", args: []string{"-v", "-v", "-v"}, want: 3},
+		{name: "long repeated", args: []string{"--verbose", "--verbose", "--verbose"}, want: 3},
+		{name: "mixed spellings", args: []string{"-vv", "--name", "x", "--verbose"}, want: 3},
+		{name: "set directly", args: []string{"--verbose=2"}, want: 2},
+		{name: "set then added to", args: []string{"--verbose=2", "-v"}, want: 3},
+		{name: "does not take the next token", args: []string{"-v", "2"}, want: 1},
 	}
 
 	for _, tt := range tests {
@@ -63,3 +68,16 @@ func Test_Verbosity_Parsing(t *testing.T) {
 		})
 	}
 }
+
+func Test_Verbosity_ShortFlagClustering(t *testing.T) {
+	type inputs struct {
+		Verbosity
+		All bool `arg:"all" short:"a"`
+	}
+	fields, err := structs.GetStructFields(&inputs{}, nil, structs.DefaultEncodingTags)
+	assertNoError(t, err)
+
+	got, err := expandShortFlags([]string{"-avvv"}, fields)
+	assertNoError(t, err)
+	assertEqual(t, []string{"-a", "-v", "-v", "-v"}, got)
+}